@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/OpenObservability/OpenMetrics/src/model"
+	"github.com/OpenObservability/OpenMetrics/src/proto/openmetrics"
+	"github.com/OpenObservability/OpenMetrics/src/validator"
+)
+
+// A list of the supported formats.
+const (
+	formatText              = "text"
+	formatProtobuf          = "protobuf"
+	formatProtobufDelimited = "protobuf-delimited"
+	formatProtoJSON         = "protojson"
+	formatPrometheusText    = "prometheus-text"
+)
+
+var validFormats = map[string]bool{
+	formatText:              true,
+	formatProtobuf:          true,
+	formatProtobufDelimited: true,
+	formatProtoJSON:         true,
+	formatPrometheusText:    true,
+}
+
+const formatsHelp = `"text", "protobuf", "protobuf-delimited", "protojson" or "prometheus-text"`
+
+var (
+	fromArg = flag.String("from", formatText, "format of the input, either "+formatsHelp)
+	toArg   = flag.String("to", formatProtobuf, "format of the output, either "+formatsHelp)
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if !validFormats[*fromArg] {
+		log.Fatalf("invalid input format %q", *fromArg)
+	}
+	if !validFormats[*toArg] {
+		log.Fatalf("invalid output format %q", *toArg)
+	}
+
+	name, b, err := readInput(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("could not read input: %v", err)
+	}
+	ms, losses, err := decode(name, b, *fromArg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var buf bytes.Buffer
+	encodeLosses, err := encode(&buf, ms, *toArg)
+	if err != nil {
+		log.Fatalf("could not convert to %s: %v", *toArg, err)
+	}
+	for _, loss := range append(losses, encodeLosses...) {
+		log.Printf("lossy conversion: %s", loss)
+	}
+	if _, err := buf.WriteTo(os.Stdout); err != nil {
+		log.Fatalf("could not write output: %v", err)
+	}
+}
+
+// readInput reads the file at path, or stdin if path is empty.
+func readInput(path string) (string, []byte, error) {
+	if path == "" {
+		b, err := ioutil.ReadAll(os.Stdin)
+		return "<stdin>", b, err
+	}
+	b, err := ioutil.ReadFile(path)
+	return path, b, err
+}
+
+// decode validates the exposition and decodes it. It returns the conversions
+// which lost information.
+func decode(name string, b []byte, format string) (*model.MetricSet, []string, error) {
+	switch format {
+	case formatText, formatPrometheusText:
+		f := validator.FormatText
+		parse := model.Parse
+		if format == formatPrometheusText {
+			f = validator.FormatPrometheusText
+			parse = model.ParsePrometheusText
+		}
+		v := validator.NewValidator(validator.ErrorLevelMust, validator.WithFormat(f))
+		if err := v.Validate(b); err != nil {
+			return nil, nil, invalidInput(name, v)
+		}
+		ms, losses, err := parse(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return ms, losses, nil
+	}
+
+	var (
+		pms *openmetrics.MetricSet
+		err error
+	)
+	switch format {
+	case formatProtobuf:
+		pms, err = validator.DecodeMetricSet(b)
+	case formatProtobufDelimited:
+		pms, err = decodeDelimited(b)
+	case formatProtoJSON:
+		pms = &openmetrics.MetricSet{}
+		err = protojson.Unmarshal(b, pms)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	v := validator.NewValidator(validator.ErrorLevelMust)
+	if err := v.ValidateMetricSet(pms); err != nil {
+		return nil, nil, invalidInput(name, v)
+	}
+	ms, err := model.FromProto(pms)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return ms, nil, nil
+}
+
+// decodeDelimited decodes length delimited MetricSet messages, which are
+// merged into one.
+func decodeDelimited(b []byte) (*openmetrics.MetricSet, error) {
+	res := &openmetrics.MetricSet{}
+	for len(b) > 0 {
+		size, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if uint64(len(b)) < size {
+			return nil, errors.New("truncated length delimited message")
+		}
+		if err := (proto.UnmarshalOptions{Merge: true}).Unmarshal(b[:size], res); err != nil {
+			return nil, err
+		}
+		b = b[size:]
+	}
+	return res, nil
+}
+
+// invalidInput logs the violations of the input and returns an error.
+func invalidInput(name string, v *validator.OpenMetricsValidator) error {
+	for _, vi := range v.Report().Violations {
+		if vi.Pos.IsValid() {
+			log.Printf("%s:%s: %v", name, vi.Pos, vi)
+		} else {
+			log.Printf("%s: %v", name, vi)
+		}
+	}
+	return errors.New("failed to validate input")
+}
+
+// encode writes the MetricSet in the format. It returns the conversions which
+// lost information.
+func encode(w io.Writer, ms *model.MetricSet, format string) ([]string, error) {
+	switch format {
+	case formatText:
+		return nil, model.Encode(w, ms)
+	case formatPrometheusText:
+		return model.EncodePrometheusText(w, ms)
+	}
+
+	pms, losses, err := model.ToProto(ms)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	switch format {
+	case formatProtobuf:
+		b, err = proto.Marshal(pms)
+	case formatProtobufDelimited:
+		if b, err = proto.Marshal(pms); err == nil {
+			b = append(protowire.AppendVarint(nil, uint64(len(b))), b...)
+		}
+	case formatProtoJSON:
+		if b, err = (protojson.MarshalOptions{Multiline: true}).Marshal(pms); err == nil {
+			b = append(b, '\n')
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	return losses, nil
+}
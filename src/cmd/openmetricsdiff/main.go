@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/OpenObservability/OpenMetrics/src/model"
+)
+
+var outputArg = flag.String("output", "text", `output format, either "text" or "json"`)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <before> <after>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *outputArg != "text" && *outputArg != "json" {
+		log.Fatalf("invalid output format %q", *outputArg)
+	}
+	before, err := parseFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	after, err := parseFile(flag.Arg(1))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	d, err := model.Diff(before, after)
+	if err != nil {
+		log.Fatalf("could not compare the expositions: %v", err)
+	}
+
+	if *outputArg == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			log.Fatalf("could not write the diff: %v", err)
+		}
+		return
+	}
+	writeText(os.Stdout, d)
+}
+
+func parseFile(path string) (*model.MetricSet, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ms, losses, err := model.Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, loss := range losses {
+		log.Printf("%s: lossy conversion: %s", path, loss)
+	}
+	return ms, nil
+}
+
+// writeText writes the diff with a "+" for additions, a "-" for removals and
+// a "~" for changes.
+func writeText(w io.Writer, d *model.MetricSetDiff) {
+	for _, name := range d.RemovedFamilies {
+		fmt.Fprintf(w, "- family %s\n", name)
+	}
+	for _, name := range d.AddedFamilies {
+		fmt.Fprintf(w, "+ family %s\n", name)
+	}
+	for _, fd := range d.ChangedFamilies {
+		fmt.Fprintf(w, "~ family %s\n", fd.Name)
+		writeChange(w, "type", fd.Type)
+		writeChange(w, "unit", fd.Unit)
+		writeChange(w, "help", fd.Help)
+		for _, lset := range fd.RemovedMetrics {
+			fmt.Fprintf(w, "    - series %s\n", lset)
+		}
+		for _, lset := range fd.AddedMetrics {
+			fmt.Fprintf(w, "    + series %s\n", lset)
+		}
+		for _, md := range fd.ChangedMetrics {
+			fmt.Fprintf(w, "    ~ series %s\n", md.Labels)
+			for _, sd := range md.Samples {
+				writeSample(w, sd)
+			}
+		}
+	}
+}
+
+func writeChange(w io.Writer, name string, c *model.Change) {
+	if c != nil {
+		fmt.Fprintf(w, "    %s: %q -> %q\n", name, c.Old, c.New)
+	}
+}
+
+func writeSample(w io.Writer, sd model.SampleDiff) {
+	switch {
+	case sd.Old == "":
+		fmt.Fprintf(w, "        + %s %s\n", sd.Name, sd.New)
+	case sd.New == "":
+		fmt.Fprintf(w, "        - %s %s\n", sd.Name, sd.Old)
+	case sd.Delta != nil:
+		delta := strconv.FormatFloat(*sd.Delta, 'g', -1, 64)
+		if *sd.Delta >= 0 {
+			delta = "+" + delta
+		}
+		fmt.Fprintf(w, "        ~ %s %s -> %s (%s)\n", sd.Name, sd.Old, sd.New, delta)
+	default:
+		fmt.Fprintf(w, "        ~ %s %s -> %s\n", sd.Name, sd.Old, sd.New)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/OpenObservability/OpenMetrics/src/model"
+	"github.com/OpenObservability/OpenMetrics/src/validator"
+)
+
+var (
+	listArg  = flag.Bool("l", false, "list files whose formatting differs from openmetricsfmt's")
+	writeArg = flag.Bool("w", false, "write result to (source) file instead of stdout")
+	diffArg  = flag.Bool("d", false, "display diffs instead of rewriting files")
+)
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		if *writeArg {
+			log.Fatalln("cannot use -w with standard input")
+		}
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("could not read input: %v", err)
+		}
+		if err := process("<stdin>", b); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		b, err := ioutil.ReadFile(path)
+		if err == nil {
+			err = process(path, b)
+		}
+		if err != nil {
+			log.Println(err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// process formats the exposition read from name and outputs the result as
+// requested by the flags.
+func process(name string, b []byte) error {
+	res, err := format(b)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if bytes.Equal(b, res) && (*listArg || *writeArg || *diffArg) {
+		return nil
+	}
+	if *listArg {
+		fmt.Println(name)
+	}
+	if *writeArg {
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(name, res, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if *diffArg {
+		d, err := diff(name, b, res)
+		if err != nil {
+			return fmt.Errorf("computing diff: %w", err)
+		}
+		fmt.Printf("diff -u %s.orig %s\n", name, name)
+		os.Stdout.Write(d)
+	}
+	if !*listArg && !*writeArg && !*diffArg {
+		os.Stdout.Write(res)
+	}
+	return nil
+}
+
+// format rewrites the exposition canonically. The "# EOF" line is appended
+// if it is missing. It refuses to format the exposition if the values of the
+// rewritten one differ, or if the rewritten one is not valid OpenMetrics.
+func format(b []byte) ([]byte, error) {
+	if !bytes.HasSuffix(bytes.TrimRight(b, "\n"), []byte("# EOF")) {
+		if len(b) > 0 && b[len(b)-1] != '\n' {
+			b = append(b, '\n')
+		}
+		b = append(b, "# EOF\n"...)
+	}
+	ms, losses, err := model.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(losses) > 0 {
+		return nil, fmt.Errorf("formatting would change the values of the exposition: %s", strings.Join(losses, ", "))
+	}
+	var buf bytes.Buffer
+	if err := model.Encode(&buf, ms); err != nil {
+		return nil, err
+	}
+	if err := checkValues(ms, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := validator.NewValidator(validator.ErrorLevelMust).Validate(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("formatted exposition is not valid OpenMetrics: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// checkValues makes sure that the formatted exposition parses to the same
+// metric set, the protobuf models are compared since NaN values make the
+// models unequal.
+func checkValues(ms *model.MetricSet, formatted []byte) error {
+	formattedMs, _, err := model.Parse(formatted)
+	if err != nil {
+		return fmt.Errorf("formatted exposition does not parse: %w", err)
+	}
+	pms, _, err := model.ToProto(ms)
+	if err != nil {
+		return err
+	}
+	formattedPms, _, err := model.ToProto(formattedMs)
+	if err != nil {
+		return err
+	}
+	if !proto.Equal(pms, formattedPms) {
+		return errors.New("formatting would change the values of the exposition")
+	}
+	return nil
+}
+
+// diff returns the unified diff of b1 and b2 using the diff command.
+func diff(name string, b1, b2 []byte) ([]byte, error) {
+	f1, err := writeTempFile(b1)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f1)
+	f2, err := writeTempFile(b2)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f2)
+
+	d, err := exec.Command("diff", "-u", "--label", name+".orig", "--label", name, f1, f2).CombinedOutput()
+	// diff exits with 1 when the files differ.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return d, nil
+	}
+	return d, err
+}
+
+func writeTempFile(b []byte) (string, error) {
+	f, err := ioutil.TempFile("", "openmetricsfmt")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -1,21 +1,85 @@
 package main
 
 import (
-	"io/ioutil"
+	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/OpenObservability/OpenMetrics/src/validator"
 )
 
+var (
+	disableRulesArg  = flag.String("disable-rules", "", `comma separated list of rules to disable, e.g. "counter.monotonic,histogram.inf-bucket"`)
+	ruleLevelsArg    = flag.String("rule-levels", "", `comma separated list of rule levels to override, e.g. "labels.duplicated-on-all-series=must"`)
+	listRulesArg     = flag.Bool("list-rules", false, "list all the rules and exit")
+	formatArg        = flag.String("format", "text", `format of the input, either "text", "protobuf" or "prometheus-text"`)
+	errorLevelArg    = flag.String("error-level", "must", `level of the rules to validate, either "must", "should" which also validates the "SHOULD" rules, or "lint" which also checks the best practices`)
+	enumStateSetsArg = flag.String("enum-statesets", "", `comma separated list of StateSet metric families which encode an ENUM, e.g. "state,mode"`)
+)
+
 func main() {
-	b, err := ioutil.ReadAll(os.Stdin)
+	flag.Parse()
+
+	if *listRulesArg {
+		for _, r := range validator.AllRules() {
+			fmt.Printf("%s\t%s\t%s\n", r.ID, r.Level, r.Description)
+		}
+		return
+	}
+
+	format, err := validator.NewFormat(*formatArg)
 	if err != nil {
-		log.Fatalf("could not read stdin: %v", err)
+		log.Fatalf("invalid format: %v", err)
 	}
-	v := validator.NewValidator(validator.ErrorLevelMust)
-	if err := v.Validate(b); err != nil {
-		log.Fatalf("failed to validate input: %v", err)
+	level, err := validator.NewErrorLevel(*errorLevelArg)
+	if err != nil {
+		log.Fatalf("invalid error level: %v", err)
+	}
+	rules, err := validator.ParseRules(*disableRulesArg, *ruleLevelsArg)
+	if err != nil {
+		log.Fatalf("invalid rules: %v", err)
+	}
+
+	name, r, err := openInput(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("could not read input: %v", err)
+	}
+	defer r.Close()
+	opts := []validator.Option{validator.WithRules(rules), validator.WithFormat(format)}
+	if *enumStateSetsArg != "" {
+		opts = append(opts, validator.WithEnumStateSets(strings.Split(*enumStateSetsArg, ",")...))
+	}
+	v := validator.NewValidator(level, opts...)
+	if err := v.ValidateReader(context.Background(), r); err != nil {
+		violations := v.Report().Violations
+		if len(violations) == 0 {
+			log.Fatalf("could not read input: %v", err)
+		}
+		for _, vi := range violations {
+			log.Println(formatViolation(name, vi))
+		}
+		log.Fatalln("failed to validate input")
 	}
 	log.Println("successfully validated input")
 }
+
+// openInput opens the file at path, or stdin if path is empty.
+func openInput(path string) (string, io.ReadCloser, error) {
+	if path == "" {
+		return "<stdin>", os.Stdin, nil
+	}
+	f, err := os.Open(path)
+	return path, f, err
+}
+
+// formatViolation formats the violation as "name:line:column: error".
+func formatViolation(name string, vi validator.Violation) string {
+	if !vi.Pos.IsValid() {
+		return name + ": " + vi.Error()
+	}
+	return name + ":" + vi.Pos.String() + ": " + vi.Error()
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/OpenObservability/OpenMetrics/src/cmd/scrapevalidator/scrape"
@@ -11,11 +12,18 @@ import (
 )
 
 var (
-	endpointArg       = flag.String("endpoint", "", "prom endpoint to validate, this is required")
-	scrapeTimeoutArg  = flag.Duration("scrape-timeout", 8*time.Second, "timeout for each scrape")
-	scrapeIntervalArg = flag.Duration("scrape-interval", 10*time.Second, "time between scrapes")
-	errorLevelArg     = flag.String("error-level", "should", `OpenMetrics defines rules in different categories like "SHOULD" and "MUST", by default this parameter is set to "should" so that it validates the rules in both the "MUST" and "SHOULD" categories, the alternative value is "must" which validates only the rules in the "MUST" category.`)
-	killAfter         = flag.Duration("kill-after", 5*time.Minute, "kill the tool after")
+	endpointArg        = flag.String("endpoint", "", "prom endpoint to validate, this is required")
+	scrapeTimeoutArg   = flag.Duration("scrape-timeout", 8*time.Second, "timeout for each scrape")
+	scrapeIntervalArg  = flag.Duration("scrape-interval", 10*time.Second, "time between scrapes")
+	errorLevelArg      = flag.String("error-level", "should", `OpenMetrics defines rules in different categories like "SHOULD" and "MUST", by default this parameter is set to "should" so that it validates the rules in both the "MUST" and "SHOULD" categories, the alternative values are "must" which validates only the rules in the "MUST" category, and "lint" which also checks best practices like the Prometheus naming conventions.`)
+	disableRulesArg    = flag.String("disable-rules", "", `comma separated list of rules to disable, e.g. "counter.monotonic,histogram.inf-bucket"`)
+	ruleLevelsArg      = flag.String("rule-levels", "", `comma separated list of rule levels to override, e.g. "labels.duplicated-on-all-series=must"`)
+	formatArg          = flag.String("format", "text", `format of the expositions, either "text", "protobuf" or "prometheus-text"`)
+	enumStateSetsArg   = flag.String("enum-statesets", "", `comma separated list of StateSet metric families which encode an ENUM, e.g. "state,mode"`)
+	timestampFutureArg = flag.Duration("timestamp-future-tolerance", time.Minute, "how far in the future sample and exemplar timestamps may be relative to the scrape, 0 disables the check of sample timestamps")
+	timestampPastArg   = flag.Duration("timestamp-past-tolerance", 0, "how far in the past sample timestamps may be relative to the scrape, 0 disables the check")
+	stateFileArg       = flag.String("state-file", "", "file the validator history is saved to after every scrape and restored from on startup, so that e.g. counter resets across restarts are detected")
+	killAfter          = flag.Duration("kill-after", 5*time.Minute, "kill the tool after")
 )
 
 func main() {
@@ -37,6 +45,24 @@ func main() {
 		}
 		opts = append(opts, scrape.WithErrorLevel(el))
 	}
+	format, err := validator.NewFormat(*formatArg)
+	if err != nil {
+		log.Fatalf("invalid format: %v", err)
+	}
+	opts = append(opts, scrape.WithFormat(format))
+	rules, err := validator.ParseRules(*disableRulesArg, *ruleLevelsArg)
+	if err != nil {
+		log.Fatalf("invalid rules: %v", err)
+	}
+	opts = append(opts, scrape.WithRules(rules))
+	if *enumStateSetsArg != "" {
+		opts = append(opts, scrape.WithEnumStateSets(strings.Split(*enumStateSetsArg, ",")...))
+	}
+
+	opts = append(opts, scrape.WithTimestampTolerance(*timestampFutureArg, *timestampPastArg))
+	if *stateFileArg != "" {
+		opts = append(opts, scrape.WithStateFile(*stateFileArg))
+	}
 
 	s := scrape.NewLoop(*endpointArg, opts...)
 	s.Run(*killAfter)
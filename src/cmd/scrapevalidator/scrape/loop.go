@@ -2,12 +2,21 @@ package scrape
 
 import (
 	"context"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/OpenObservability/OpenMetrics/src/validator"
 )
 
+// _acceptHeaders are the Accept headers of the scrapes by format.
+var _acceptHeaders = map[validator.Format]string{
+	validator.FormatProtobuf:       "application/openmetrics-protobuf; version=1.0.0",
+	validator.FormatPrometheusText: "text/plain; version=0.0.4",
+}
+
 // Option sets options in Loop.
 type Option func(*Loop)
 
@@ -28,16 +37,58 @@ func WithScrapeInterval(interval time.Duration) Option {
 // WithErrorLevel sets the error level.
 func WithErrorLevel(el validator.ErrorLevel) Option {
 	return func(l *Loop) {
-		l.validator = validator.NewValidator(el)
+		l.errorLevel = el
+	}
+}
+
+// WithRules sets the rules configuration of the validator.
+func WithRules(rules validator.Rules) Option {
+	return func(l *Loop) {
+		l.validatorOpts = append(l.validatorOpts, validator.WithRules(rules))
+	}
+}
+
+// WithFormat sets the format of the expositions, the default is the text format.
+func WithFormat(format validator.Format) Option {
+	return func(l *Loop) {
+		l.format = format
+	}
+}
+
+// WithEnumStateSets sets the StateSet metric families which encode an ENUM.
+func WithEnumStateSets(mfns ...string) Option {
+	return func(l *Loop) {
+		l.validatorOpts = append(l.validatorOpts, validator.WithEnumStateSets(mfns...))
+	}
+}
+
+// WithTimestampTolerance sets how far in the future and in the past sample
+// timestamps may be relative to the scrape, zero disables the check.
+func WithTimestampTolerance(future, past time.Duration) Option {
+	return func(l *Loop) {
+		l.validatorOpts = append(l.validatorOpts, validator.WithTimestampTolerance(future, past))
+	}
+}
+
+// WithStateFile sets the file the history of the validator is saved to after
+// every scrape, and restored from when the loop is created, so that the
+// validation survives restarts.
+func WithStateFile(path string) Option {
+	return func(l *Loop) {
+		l.stateFile = path
 	}
 }
 
 // Loop and perform scrape and validate in a loop.
 type Loop struct {
 	validator      *validator.OpenMetricsValidator
+	errorLevel     validator.ErrorLevel
+	validatorOpts  []validator.Option
+	format         validator.Format
 	scraper        scraper
 	scrapeTimeout  time.Duration
 	scrapeInterval time.Duration
+	stateFile      string
 }
 
 // NewLoop creates a new scrape and validate loop.
@@ -46,12 +97,19 @@ func NewLoop(
 	opts ...Option,
 ) *Loop {
 	l := &Loop{
-		validator: validator.NewValidator(validator.ErrorLevelMust),
-		scraper:   newSimpleScraper(endpoint),
+		errorLevel: validator.ErrorLevelMust,
 	}
 	for _, opt := range opts {
 		opt(l)
 	}
+	l.scraper = newSimpleScraper(endpoint, _acceptHeaders[l.format])
+	l.validator = validator.NewValidator(l.errorLevel,
+		append(l.validatorOpts, validator.WithFormat(l.format), validator.WithLiveTarget())...)
+	if l.stateFile != "" {
+		if err := l.loadState(); err != nil {
+			log.Printf("could not restore the validator state: %v\n", err)
+		}
+	}
 	return l
 }
 
@@ -73,20 +131,73 @@ func (l *Loop) Run(killAfter time.Duration) {
 }
 
 func (l *Loop) runOnce() {
+	if l.stateFile != "" {
+		defer func() {
+			if err := l.saveState(); err != nil {
+				log.Printf("could not save the validator state: %v\n", err)
+			}
+		}()
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), l.scrapeTimeout)
 	defer cancel()
 
-	b, err := l.scraper.Scrape(ctx)
+	body, err := l.scraper.Scrape(ctx)
 	if err != nil {
 		log.Printf("scrape failed: %v\n", err)
 		return
 	}
+	defer body.Close()
 	log.Println("scraped successfully")
 
-	if err := l.validator.Validate(b); err != nil {
-		l.validator.Reset()
-		log.Printf("validation failed: %v\n", err)
+	if err := l.validator.ValidateReader(ctx, body); err != nil {
+		violations := l.validator.Report().Violations
+		if len(violations) == 0 {
+			// The body could not be read, e.g. the scrape timed out.
+			log.Printf("scrape failed: %v\n", err)
+		}
+		// The report only holds the violations of this scrape and an
+		// exposition which cannot be read or parsed is discarded, the history
+		// is kept so the next scrape is still compared with the last parsed one.
+		for _, vi := range violations {
+			log.Printf("validation failed at %s: %v\n", vi.Pos, vi)
+		}
 		return
 	}
 	log.Println("validated successfully")
 }
+
+// loadState restores the history of the validator from the state file, a
+// missing file is not an error.
+func (l *Loop) loadState() error {
+	f, err := os.Open(l.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := l.validator.LoadState(f); err != nil {
+		return err
+	}
+	log.Printf("restored the validator state from %s\n", l.stateFile)
+	return nil
+}
+
+// saveState saves the history of the validator to the state file. The state
+// is written to a temporary file first so a crash does not corrupt it.
+func (l *Loop) saveState() error {
+	f, err := ioutil.TempFile(filepath.Dir(l.stateFile), filepath.Base(l.stateFile)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := l.validator.SaveState(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), l.stateFile)
+}
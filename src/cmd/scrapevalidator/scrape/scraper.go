@@ -2,26 +2,37 @@ package scrape
 
 import (
 	"context"
-	"io/ioutil"
+	"io"
 	"net/http"
 )
 
 type scraper interface {
-	Scrape(ctx context.Context) ([]byte, error)
+	Scrape(ctx context.Context) (io.ReadCloser, error)
 }
 
 type simpleScraper struct {
 	addr string
+	// accept is the Accept header of the requests, if not empty.
+	accept string
 }
 
-func newSimpleScraper(addr string) *simpleScraper {
-	return &simpleScraper{addr: addr}
+func newSimpleScraper(addr, accept string) *simpleScraper {
+	return &simpleScraper{addr: addr, accept: accept}
 }
 
-func (s simpleScraper) Scrape(ctx context.Context) ([]byte, error) {
-	resp, err := http.Get(s.addr)
+// Scrape requests the endpoint and returns the body of the response, which
+// must be closed by the caller.
+func (s simpleScraper) Scrape(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr, nil)
 	if err != nil {
 		return nil, err
 	}
-	return ioutil.ReadAll(resp.Body)
+	if s.accept != "" {
+		req.Header.Set("Accept", s.accept)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
 }
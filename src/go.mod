@@ -4,7 +4,9 @@ go 1.16
 
 require (
 	github.com/linode/linodego v1.2.1 // indirect
+	github.com/prometheus/common v0.29.0
 	github.com/prometheus/prometheus v1.8.2-0.20210629155649-1a1394fc5873
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/multierr v1.7.0
+	google.golang.org/protobuf v1.26.0
 )
@@ -0,0 +1,195 @@
+package model
+
+import "math"
+
+// MetricSetDiff is the difference between two MetricSets.
+type MetricSetDiff struct {
+	AddedFamilies   []string           `json:"added_families,omitempty"`
+	RemovedFamilies []string           `json:"removed_families,omitempty"`
+	ChangedFamilies []MetricFamilyDiff `json:"changed_families,omitempty"`
+}
+
+// Empty reports whether the MetricSets are the same.
+func (d *MetricSetDiff) Empty() bool {
+	return len(d.AddedFamilies) == 0 && len(d.RemovedFamilies) == 0 && len(d.ChangedFamilies) == 0
+}
+
+// MetricFamilyDiff is the difference between two versions of a MetricFamily.
+// Metrics are identified by their labels, e.g. `{a="b"}`.
+type MetricFamilyDiff struct {
+	Name           string       `json:"name"`
+	Type           *Change      `json:"type,omitempty"`
+	Unit           *Change      `json:"unit,omitempty"`
+	Help           *Change      `json:"help,omitempty"`
+	AddedMetrics   []string     `json:"added_metrics,omitempty"`
+	RemovedMetrics []string     `json:"removed_metrics,omitempty"`
+	ChangedMetrics []MetricDiff `json:"changed_metrics,omitempty"`
+}
+
+func (d *MetricFamilyDiff) empty() bool {
+	return d.Type == nil && d.Unit == nil && d.Help == nil &&
+		len(d.AddedMetrics) == 0 && len(d.RemovedMetrics) == 0 && len(d.ChangedMetrics) == 0
+}
+
+// Change is a changed metadata value.
+type Change struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// MetricDiff is the difference between the values of two versions of a Metric.
+type MetricDiff struct {
+	Labels  string       `json:"labels"`
+	Samples []SampleDiff `json:"samples"`
+}
+
+// SampleDiff is a changed sample of a Metric.
+type SampleDiff struct {
+	// Name is the sample name followed by the labels specific to the sample,
+	// e.g. `foo_bucket{le="1.0"}`.
+	Name string `json:"name"`
+	// Old is empty if the sample was added, and New if it was removed.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+	// Delta is the new value minus the old value, it is nil unless both
+	// values are finite.
+	Delta *float64 `json:"delta,omitempty"`
+}
+
+// Diff compares the MetricSet before a change with the one after it.
+// MetricFamilies are matched by name and Metrics by labels, and the values of
+// the last MetricPoints of the Metrics are compared. Values are not compared
+// when the type of a MetricFamily changed.
+func Diff(before, after *MetricSet) (*MetricSetDiff, error) {
+	d := &MetricSetDiff{}
+	newFamilies := make(map[string]*MetricFamily, len(after.MetricFamilies))
+	for _, mf := range after.MetricFamilies {
+		newFamilies[mf.Name] = mf
+	}
+	oldFamilies := make(map[string]bool, len(before.MetricFamilies))
+	for _, oldMF := range before.MetricFamilies {
+		oldFamilies[oldMF.Name] = true
+		newMF, ok := newFamilies[oldMF.Name]
+		if !ok {
+			d.RemovedFamilies = append(d.RemovedFamilies, oldMF.Name)
+			continue
+		}
+		fd, err := diffMetricFamily(oldMF, newMF)
+		if err != nil {
+			return nil, err
+		}
+		if !fd.empty() {
+			d.ChangedFamilies = append(d.ChangedFamilies, *fd)
+		}
+	}
+	for _, mf := range after.MetricFamilies {
+		if !oldFamilies[mf.Name] {
+			d.AddedFamilies = append(d.AddedFamilies, mf.Name)
+		}
+	}
+	return d, nil
+}
+
+func diffMetricFamily(before, after *MetricFamily) (*MetricFamilyDiff, error) {
+	d := &MetricFamilyDiff{
+		Name: before.Name,
+		Type: change(before.Type.String(), after.Type.String()),
+		Unit: change(before.Unit, after.Unit),
+		Help: change(before.Help, after.Help),
+	}
+
+	newMetrics := make(map[string]*Metric, len(after.Metrics))
+	for _, m := range after.Metrics {
+		newMetrics[m.Labels.String()] = m
+	}
+	oldMetrics := make(map[string]bool, len(before.Metrics))
+	for _, oldM := range before.Metrics {
+		key := oldM.Labels.String()
+		oldMetrics[key] = true
+		newM, ok := newMetrics[key]
+		if !ok {
+			d.RemovedMetrics = append(d.RemovedMetrics, key)
+			continue
+		}
+		if d.Type != nil {
+			continue
+		}
+		samples, err := diffMetric(before, oldM, newM)
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) > 0 {
+			d.ChangedMetrics = append(d.ChangedMetrics, MetricDiff{Labels: key, Samples: samples})
+		}
+	}
+	for _, m := range after.Metrics {
+		if key := m.Labels.String(); !oldMetrics[key] {
+			d.AddedMetrics = append(d.AddedMetrics, key)
+		}
+	}
+	return d, nil
+}
+
+func change(before, after string) *Change {
+	if before == after {
+		return nil
+	}
+	return &Change{Old: before, New: after}
+}
+
+// diffMetric compares the last MetricPoints of two versions of a Metric.
+func diffMetric(mf *MetricFamily, before, after *Metric) ([]SampleDiff, error) {
+	oldSamples, err := lastSamples(mf, before)
+	if err != nil {
+		return nil, err
+	}
+	newSamples, err := lastSamples(mf, after)
+	if err != nil {
+		return nil, err
+	}
+
+	newByName := make(map[string]sample, len(newSamples))
+	for _, s := range newSamples {
+		newByName[sampleName(mf, s)] = s
+	}
+	var res []SampleDiff
+	oldNames := make(map[string]bool, len(oldSamples))
+	for _, oldS := range oldSamples {
+		name := sampleName(mf, oldS)
+		oldNames[name] = true
+		newS, ok := newByName[name]
+		if !ok {
+			res = append(res, SampleDiff{Name: name, Old: oldS.text})
+			continue
+		}
+		if oldS.text == newS.text {
+			continue
+		}
+		sd := SampleDiff{Name: name, Old: oldS.text, New: newS.text}
+		if delta := newS.value - oldS.value; !math.IsNaN(delta) && !math.IsInf(delta, 0) {
+			sd.Delta = &delta
+		}
+		res = append(res, sd)
+	}
+	for _, s := range newSamples {
+		if name := sampleName(mf, s); !oldNames[name] {
+			res = append(res, SampleDiff{Name: name, New: s.text})
+		}
+	}
+	return res, nil
+}
+
+func lastSamples(mf *MetricFamily, m *Metric) ([]sample, error) {
+	if len(m.MetricPoints) == 0 {
+		return nil, nil
+	}
+	return metricPointSamples(mf, m.MetricPoints[len(m.MetricPoints)-1])
+}
+
+func sampleName(mf *MetricFamily, s sample) string {
+	name := mf.Name + s.suffix
+	if len(s.labels) > 0 {
+		name += s.labels.String()
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before, _, err := Parse([]byte(`# TYPE a counter
+# HELP a old help
+a_total{b="1"} 1
+a_total{b="2"} 1
+# TYPE c histogram
+c_bucket{le="1"} 1
+c_bucket{le="+Inf"} 2
+c_count 2
+c_sum 3
+# TYPE d gauge
+d 1
+# TYPE e gauge
+e 1
+# EOF
+`))
+	require.NoError(t, err)
+	after, _, err := Parse([]byte(`# TYPE a counter
+# HELP a new help
+a_total{b="1"} 3.5
+a_total{b="3"} 1
+# TYPE c histogram
+c_bucket{le="2"} 1
+c_bucket{le="+Inf"} 2
+c_count 2
+c_sum 3
+# TYPE d counter
+d_total 1
+# TYPE f gauge
+f 1
+# EOF
+`))
+	require.NoError(t, err)
+
+	d, err := Diff(before, after)
+	require.NoError(t, err)
+	delta := 2.5
+	require.Equal(t, &MetricSetDiff{
+		AddedFamilies:   []string{"f"},
+		RemovedFamilies: []string{"e"},
+		ChangedFamilies: []MetricFamilyDiff{
+			{
+				Name:           "a",
+				Help:           &Change{Old: "old help", New: "new help"},
+				AddedMetrics:   []string{`{b="3"}`},
+				RemovedMetrics: []string{`{b="2"}`},
+				ChangedMetrics: []MetricDiff{{
+					Labels:  `{b="1"}`,
+					Samples: []SampleDiff{{Name: "a_total", Old: "1", New: "3.5", Delta: &delta}},
+				}},
+			},
+			{
+				Name: "c",
+				ChangedMetrics: []MetricDiff{{
+					Labels: "{}",
+					Samples: []SampleDiff{
+						{Name: `c_bucket{le="1.0"}`, Old: "1"},
+						{Name: `c_bucket{le="2.0"}`, New: "1"},
+					},
+				}},
+			},
+			{
+				Name: "d",
+				Type: &Change{Old: "gauge", New: "counter"},
+			},
+		},
+	}, d)
+	require.False(t, d.Empty())
+
+	d, err = Diff(before, before)
+	require.NoError(t, err)
+	require.True(t, d.Empty())
+}
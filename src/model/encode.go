@@ -0,0 +1,313 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pmodel "github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+var _escaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// Encode writes the MetricSet in the canonical OpenMetrics text format:
+//   - the metadata of a metric family is written in the TYPE, UNIT, HELP order,
+//     and UNIT and HELP are omitted when empty,
+//   - the samples of a MetricPoint are written in the order of the spec, with
+//     the buckets and quantiles sorted,
+//   - floats, including "le" and "quantile" label values, are canonical numbers,
+//   - label values and HELP are escaped, and the exposition ends with "# EOF".
+//
+// Encode only checks what the text format needs, it returns an error and
+// writes nothing if a name cannot be written, e.g. a label name with a dash,
+// or if a MetricPoint cannot be exposed, e.g. a histogram count without a sum.
+// The values are written as they are, use the validator to check the MetricSet
+// against the rest of the OpenMetrics spec.
+func Encode(w io.Writer, ms *MetricSet) error {
+	var buf bytes.Buffer
+	written := make(map[string]bool, len(ms.MetricFamilies))
+	for _, mf := range ms.MetricFamilies {
+		if written[mf.Name] {
+			return fmt.Errorf("metric family %q is in the MetricSet twice", mf.Name)
+		}
+		written[mf.Name] = true
+		if err := encodeMetricFamily(&buf, mf); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("# EOF\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func encodeMetricFamily(buf *bytes.Buffer, mf *MetricFamily) error {
+	if err := checkMetricFamilyName(mf); err != nil {
+		return err
+	}
+	if mf.Unit != "" && !strings.HasSuffix(mf.Name, "_"+mf.Unit) {
+		return fmt.Errorf("unit %q is not a suffix of metric family %q", mf.Unit, mf.Name)
+	}
+	fmt.Fprintf(buf, "# TYPE %s %s\n", mf.Name, mf.Type)
+	if mf.Unit != "" {
+		fmt.Fprintf(buf, "# UNIT %s %s\n", mf.Name, mf.Unit)
+	}
+	if mf.Help != "" {
+		fmt.Fprintf(buf, "# HELP %s %s\n", mf.Name, _escaper.Replace(mf.Help))
+	}
+	for _, m := range mf.Metrics {
+		for _, mp := range m.MetricPoints {
+			if err := encodeMetricPoint(buf, mf, m.Labels, mp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sample is a sample a MetricPoint is exposed as.
+type sample struct {
+	suffix string
+	// labels are the labels of the sample in addition to the ones of the
+	// Metric, e.g. "le" for buckets.
+	labels labels.Labels
+	// text is the value as written in the exposition.
+	text     string
+	value    float64
+	exemplar *Exemplar
+}
+
+func encodeMetricPoint(buf *bytes.Buffer, mf *MetricFamily, lset labels.Labels, mp *MetricPoint) error {
+	samples, err := metricPointSamples(mf, mp)
+	if err != nil {
+		return err
+	}
+	for _, s := range samples {
+		sampleLset := append(append(labels.Labels{}, lset...), s.labels...)
+		if err := checkLabelNames(mf, sampleLset); err != nil {
+			return err
+		}
+		if s.exemplar != nil {
+			if err := checkLabelNames(mf, s.exemplar.Labels); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(mf.Name + s.suffix)
+		writeLabels(buf, sampleLset)
+		buf.WriteString(" " + s.text)
+		if mp.Timestamp != nil {
+			buf.WriteString(" " + formatTimestamp(*mp.Timestamp))
+		}
+		if e := s.exemplar; e != nil {
+			buf.WriteString(" # ")
+			writeLabels(buf, e.Labels)
+			if len(e.Labels) == 0 {
+				buf.WriteString("{}")
+			}
+			buf.WriteString(" " + FormatFloat(e.Value))
+			if e.Timestamp != nil {
+				buf.WriteString(" " + formatTimestamp(*e.Timestamp))
+			}
+		}
+		buf.WriteString("\n")
+	}
+	return nil
+}
+
+// metricPointSamples returns the samples the MetricPoint is exposed as, in the
+// order of the spec.
+func metricPointSamples(mf *MetricFamily, mp *MetricPoint) ([]sample, error) {
+	var samples []sample
+	number := func(suffix string, extra labels.Labels, n Number, e *Exemplar) {
+		samples = append(samples, sample{suffix: suffix, labels: extra, text: FormatNumber(n), value: n.Float64(), exemplar: e})
+	}
+	float := func(suffix string, extra labels.Labels, f float64) {
+		samples = append(samples, sample{suffix: suffix, labels: extra, text: FormatFloat(f), value: f})
+	}
+	count := func(suffix string, extra labels.Labels, c uint64, e *Exemplar) {
+		samples = append(samples, sample{suffix: suffix, labels: extra, text: strconv.FormatUint(c, 10), value: float64(c), exemplar: e})
+	}
+	created := func(t *time.Time) {
+		if t != nil {
+			samples = append(samples, sample{
+				suffix: "_created",
+				text:   formatTimestamp(*t),
+				value:  float64(t.Unix()) + float64(t.Nanosecond())/float64(time.Second),
+			})
+		}
+	}
+
+	switch v := mp.Value.(type) {
+	case *UnknownValue:
+		if mf.Type != MetricTypeUnknown {
+			break
+		}
+		number("", nil, v.Value, nil)
+		return samples, nil
+	case *GaugeValue:
+		if mf.Type != MetricTypeGauge {
+			break
+		}
+		number("", nil, v.Value, nil)
+		return samples, nil
+	case *CounterValue:
+		if mf.Type != MetricTypeCounter {
+			break
+		}
+		number("_total", nil, v.Total, v.Exemplar)
+		created(v.Created)
+		return samples, nil
+	case *HistogramValue:
+		if mf.Type != MetricTypeHistogram && mf.Type != MetricTypeGaugeHistogram {
+			break
+		}
+		buckets := append([]Bucket(nil), v.Buckets...)
+		sort.SliceStable(buckets, func(i, j int) bool {
+			return buckets[i].UpperBound < buckets[j].UpperBound
+		})
+		for _, b := range buckets {
+			count("_bucket", labels.Labels{{Name: labels.BucketLabel, Value: FormatFloat(b.UpperBound)}}, b.Count, b.Exemplar)
+		}
+		countSuffix, sumSuffix := "_count", "_sum"
+		if mf.Type == MetricTypeGaugeHistogram {
+			countSuffix, sumSuffix = "_gcount", "_gsum"
+		}
+		// The count is only exposed along with the sum, a count without a sum
+		// cannot be encoded rather than being dropped.
+		if v.Sum == nil && v.Count != 0 {
+			return nil, fmt.Errorf("%v MetricPoint of metric family %q has a count but no sum", mf.Type, mf.Name)
+		}
+		if v.Sum != nil {
+			count(countSuffix, nil, v.Count, nil)
+			number(sumSuffix, nil, *v.Sum, nil)
+		}
+		if mf.Type == MetricTypeHistogram {
+			created(v.Created)
+		}
+		return samples, nil
+	case *StateSetValue:
+		if mf.Type != MetricTypeStateSet {
+			break
+		}
+		for _, s := range v.States {
+			var enabled int64
+			if s.Enabled {
+				enabled = 1
+			}
+			number("", labels.Labels{{Name: mf.Name, Value: s.Name}}, IntNumber(enabled), nil)
+		}
+		return samples, nil
+	case *InfoValue:
+		if mf.Type != MetricTypeInfo {
+			break
+		}
+		number("_info", nil, IntNumber(1), nil)
+		return samples, nil
+	case *SummaryValue:
+		if mf.Type != MetricTypeSummary {
+			break
+		}
+		quantiles := append([]Quantile(nil), v.Quantiles...)
+		sort.SliceStable(quantiles, func(i, j int) bool {
+			return quantiles[i].Quantile < quantiles[j].Quantile
+		})
+		for _, q := range quantiles {
+			float("", labels.Labels{{Name: "quantile", Value: FormatFloat(q.Quantile)}}, q.Value)
+		}
+		if v.Sum != nil || v.Count != 0 {
+			count("_count", nil, v.Count, nil)
+		}
+		if v.Sum != nil {
+			number("_sum", nil, *v.Sum, nil)
+		}
+		created(v.Created)
+		return samples, nil
+	}
+	return nil, fmt.Errorf("MetricPoint value %T of metric family %q does not match its type %v", mp.Value, mf.Name, mf.Type)
+}
+
+// checkMetricFamilyName makes sure that the name of the metric family can be
+// written, names have no escaping in the text formats.
+func checkMetricFamilyName(mf *MetricFamily) error {
+	if !pmodel.IsValidMetricName(pmodel.LabelValue(mf.Name)) {
+		return fmt.Errorf("invalid metric family name %q", mf.Name)
+	}
+	return nil
+}
+
+// checkLabelNames makes sure that the label names can be written and tell
+// the labels apart.
+func checkLabelNames(mf *MetricFamily, lset labels.Labels) error {
+	seen := make(map[string]bool, len(lset))
+	for _, l := range lset {
+		if !pmodel.LabelName(l.Name).IsValid() {
+			return fmt.Errorf("invalid label name %q in metric family %q", l.Name, mf.Name)
+		}
+		if seen[l.Name] {
+			return fmt.Errorf("duplicate label name %q in metric family %q", l.Name, mf.Name)
+		}
+		seen[l.Name] = true
+	}
+	return nil
+}
+
+func writeLabels(buf *bytes.Buffer, lset labels.Labels) {
+	if len(lset) == 0 {
+		return
+	}
+	buf.WriteByte('{')
+	for i, l := range lset {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(l.Name + `="` + _escaper.Replace(l.Value) + `"`)
+	}
+	buf.WriteByte('}')
+}
+
+// FormatNumber formats a Number, integers are written without a fraction and
+// floats as canonical numbers.
+func FormatNumber(n Number) string {
+	switch {
+	case n.IsInt:
+		return strconv.FormatInt(n.Int, 10)
+	case n.IsUint:
+		return strconv.FormatUint(n.Uint, 10)
+	}
+	return FormatFloat(n.Float)
+}
+
+// FormatFloat formats a float as a canonical number, which is the %g
+// rendering with ".0" appended to integers so they are still read as floats.
+// It is the rendering "le" and "quantile" label values must use.
+func FormatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if math.IsInf(f, 0) || math.IsNaN(f) || strings.ContainsAny(s, ".e") {
+		return s
+	}
+	return s + ".0"
+}
+
+// formatTimestamp formats a timestamp in seconds, with up to nanosecond
+// precision.
+func formatTimestamp(t time.Time) string {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	negative := sec < 0
+	if negative && nsec > 0 {
+		sec, nsec = sec+1, int64(time.Second)-nsec
+	}
+	s := strconv.FormatInt(sec, 10)
+	if negative && sec == 0 {
+		s = "-0"
+	}
+	if nsec > 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%09d", nsec), "0")
+	}
+	return s
+}
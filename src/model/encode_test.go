@@ -0,0 +1,255 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/OpenObservability/OpenMetrics/src/validator"
+)
+
+func TestEncode(t *testing.T) {
+	ts := time.Unix(1, 500000000).UTC()
+	sum := IntNumber(3)
+	ms := &MetricSet{MetricFamilies: []*MetricFamily{
+		{
+			Name: "a_seconds",
+			Type: MetricTypeCounter,
+			Unit: "seconds",
+			Help: "help with \\ and \"\n",
+			Metrics: []*Metric{{
+				Labels: labels.FromStrings("b", "x\"y"),
+				MetricPoints: []*MetricPoint{{
+					Value: &CounterValue{
+						Total:    FloatNumber(1),
+						Created:  &ts,
+						Exemplar: &Exemplar{Value: 0.5, Labels: labels.FromStrings("trace_id", "\n")},
+					},
+					Timestamp: &ts,
+				}},
+			}},
+		},
+		{
+			Name: "b",
+			Type: MetricTypeHistogram,
+			Metrics: []*Metric{{
+				MetricPoints: []*MetricPoint{{Value: &HistogramValue{
+					Sum:     &sum,
+					Count:   2,
+					Buckets: []Bucket{{Count: 2, UpperBound: math.Inf(1)}, {Count: 1, UpperBound: 1}},
+				}}},
+			}},
+		},
+		{
+			Name: "c",
+			Type: MetricTypeSummary,
+			Metrics: []*Metric{{
+				MetricPoints: []*MetricPoint{{Value: &SummaryValue{
+					Quantiles: []Quantile{{Quantile: 0.99, Value: 2}, {Quantile: 1e-05, Value: 1}},
+				}}},
+			}},
+		},
+		{
+			Name: "d",
+			Metrics: []*Metric{{
+				MetricPoints: []*MetricPoint{{Value: &UnknownValue{Value: FloatNumber(math.Inf(-1))}}},
+			}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, ms))
+	require.Equal(t, `# TYPE a_seconds counter
+# UNIT a_seconds seconds
+# HELP a_seconds help with \\ and \"\n
+a_seconds_total{b="x\"y"} 1.0 1.5 # {trace_id="\n"} 0.5
+a_seconds_created{b="x\"y"} 1.5 1.5
+# TYPE b histogram
+b_bucket{le="1.0"} 1
+b_bucket{le="+Inf"} 2
+b_count 2
+b_sum 3
+# TYPE c summary
+c{quantile="1e-05"} 1.0
+c{quantile="0.99"} 2.0
+# TYPE d unknown
+d -Inf
+# EOF
+`, buf.String())
+}
+
+func TestEncodeKeepsValues(t *testing.T) {
+	b := []byte(`# TYPE a counter
+a_total 9223372036854775808
+# TYPE b gauge
+b{c="1"} 18446744073709551615
+b{c="2"} -9223372036854775808
+b{c="3"} 9007199254740993
+b{c="4"} 0.1
+# EOF
+`)
+	ms, _, err := Parse(b)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, ms))
+	require.Equal(t, string(b), buf.String())
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	tcs := []struct {
+		name        string
+		mf          *MetricFamily
+		expectedErr string
+	}{
+		{
+			name: "value_does_not_match_type",
+			mf: &MetricFamily{
+				Name:    "a",
+				Type:    MetricTypeCounter,
+				Metrics: []*Metric{{MetricPoints: []*MetricPoint{{Value: &GaugeValue{}}}}},
+			},
+			expectedErr: `MetricPoint value *model.GaugeValue of metric family "a" does not match its type counter`,
+		},
+		{
+			name: "histogram_count_without_sum",
+			mf: &MetricFamily{
+				Name: "a",
+				Type: MetricTypeGaugeHistogram,
+				Metrics: []*Metric{{MetricPoints: []*MetricPoint{{Value: &HistogramValue{
+					Count:   1,
+					Buckets: []Bucket{{Count: 1, UpperBound: math.Inf(1)}},
+				}}}}},
+			},
+			expectedErr: `gaugehistogram MetricPoint of metric family "a" has a count but no sum`,
+		},
+		{
+			name:        "invalid_metric_family_name",
+			mf:          &MetricFamily{Name: "a-b", Type: MetricTypeGauge},
+			expectedErr: `invalid metric family name "a-b"`,
+		},
+		{
+			name:        "unit_not_name_suffix",
+			mf:          &MetricFamily{Name: "a", Type: MetricTypeGauge, Unit: "seconds"},
+			expectedErr: `unit "seconds" is not a suffix of metric family "a"`,
+		},
+		{
+			name: "invalid_label_name",
+			mf: &MetricFamily{
+				Name: "a",
+				Type: MetricTypeGauge,
+				Metrics: []*Metric{{
+					Labels:       labels.Labels{{Name: "b-c", Value: "1"}},
+					MetricPoints: []*MetricPoint{{Value: &GaugeValue{}}},
+				}},
+			},
+			expectedErr: `invalid label name "b-c" in metric family "a"`,
+		},
+		{
+			name: "duplicate_label_name",
+			mf: &MetricFamily{
+				Name: "a",
+				Type: MetricTypeHistogram,
+				Metrics: []*Metric{{
+					Labels: labels.Labels{{Name: "le", Value: "1"}},
+					MetricPoints: []*MetricPoint{{Value: &HistogramValue{
+						Buckets: []Bucket{{Count: 1, UpperBound: math.Inf(1)}},
+					}}},
+				}},
+			},
+			expectedErr: `duplicate label name "le" in metric family "a"`,
+		},
+		{
+			name: "invalid_exemplar_label_name",
+			mf: &MetricFamily{
+				Name: "a",
+				Type: MetricTypeCounter,
+				Metrics: []*Metric{{MetricPoints: []*MetricPoint{{Value: &CounterValue{
+					Exemplar: &Exemplar{Labels: labels.Labels{{Name: "0", Value: "1"}}},
+				}}}}},
+			},
+			expectedErr: `invalid label name "0" in metric family "a"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Encode(&buf, &MetricSet{MetricFamilies: []*MetricFamily{tc.mf}})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErr)
+			require.Zero(t, buf.Len())
+		})
+	}
+}
+
+func TestEncodeDuplicateMetricFamily(t *testing.T) {
+	mf := &MetricFamily{Name: "a", Type: MetricTypeGauge}
+	var buf bytes.Buffer
+	err := Encode(&buf, &MetricSet{MetricFamilies: []*MetricFamily{mf, mf}})
+	require.EqualError(t, err, `metric family "a" is in the MetricSet twice`)
+	require.Zero(t, buf.Len())
+}
+
+// TestRoundtrip makes sure that the expositions of the test suite which should
+// parse survive a parse, encode, parse round trip, and that the encodings are
+// valid OpenMetrics. The encodings and the protobuf models are compared
+// since NaN values make the models unequal.
+func TestRoundtrip(t *testing.T) {
+	dirs, err := filepath.Glob("../../tests/testdata/parsers/*")
+	require.NoError(t, err)
+	require.Contains(t, dirs, "../../tests/testdata/parsers/roundtrip")
+	for _, dir := range dirs {
+		if !shouldParse(t, dir) {
+			continue
+		}
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			b, err := ioutil.ReadFile(filepath.Join(dir, "metrics"))
+			require.NoError(t, err)
+			ms, _, err := Parse(b)
+			require.NoError(t, err)
+
+			var encoded bytes.Buffer
+			require.NoError(t, Encode(&encoded, ms))
+			v := validator.NewValidator(validator.ErrorLevelMust)
+			require.NoError(t, v.Validate(encoded.Bytes()), "invalid encoding:\n%s", encoded.String())
+			roundtripped, _, err := Parse(encoded.Bytes())
+			require.NoError(t, err)
+			pms, _, err := ToProto(ms)
+			require.NoError(t, err)
+			roundtrippedPms, _, err := ToProto(roundtripped)
+			require.NoError(t, err)
+			require.True(t, proto.Equal(pms, roundtrippedPms), "values changed:\n%s", encoded.String())
+
+			var reencoded bytes.Buffer
+			require.NoError(t, Encode(&reencoded, roundtripped))
+			require.Equal(t, encoded.String(), reencoded.String())
+		})
+	}
+}
+
+// shouldParse returns whether the exposition of the test case in dir should
+// parse.
+func shouldParse(t *testing.T, dir string) bool {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "test.json"))
+	require.NoError(t, err)
+	var tc struct {
+		ShouldParse bool `json:"shouldParse"`
+	}
+	require.NoError(t, json.Unmarshal(b, &tc))
+	return tc.ShouldParse
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	for _, s := range []string{"0", "1", "-1", "1.5", "-1.5", "-0.5", "1520430000.123", "0.000000001"} {
+		ts, err := parseTimestamp(s)
+		require.NoError(t, err)
+		require.Equal(t, s, formatTimestamp(ts))
+	}
+}
@@ -0,0 +1,276 @@
+// Package model is an in-memory representation of OpenMetrics expositions
+// which mirrors the OpenMetrics protobuf data model.
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// MetricType is the type of a MetricFamily.
+type MetricType int
+
+// A list of the metric types, in the order of the protobuf enum.
+const (
+	MetricTypeUnknown MetricType = iota
+	MetricTypeGauge
+	MetricTypeCounter
+	MetricTypeStateSet
+	MetricTypeInfo
+	MetricTypeHistogram
+	MetricTypeGaugeHistogram
+	MetricTypeSummary
+)
+
+var _metricTypeNames = map[MetricType]string{
+	MetricTypeUnknown:        "unknown",
+	MetricTypeGauge:          "gauge",
+	MetricTypeCounter:        "counter",
+	MetricTypeStateSet:       "stateset",
+	MetricTypeInfo:           "info",
+	MetricTypeHistogram:      "histogram",
+	MetricTypeGaugeHistogram: "gaugehistogram",
+	MetricTypeSummary:        "summary",
+}
+
+// String returns the metric type as in the TYPE line of the text format.
+func (mt MetricType) String() string {
+	return _metricTypeNames[mt]
+}
+
+// MetricSet is the top level object of an exposition.
+type MetricSet struct {
+	MetricFamilies []*MetricFamily
+}
+
+// MetricFamily is a set of Metrics of the same type.
+type MetricFamily struct {
+	Name    string
+	Type    MetricType
+	Unit    string
+	Help    string
+	Metrics []*Metric
+}
+
+// Metric is a time series identified by its labels.
+type Metric struct {
+	// Labels does not contain the metric name.
+	Labels       labels.Labels
+	MetricPoints []*MetricPoint
+}
+
+// MetricPoint is the value of a Metric at a point in time.
+type MetricPoint struct {
+	// Value is one of *UnknownValue, *GaugeValue, *CounterValue,
+	// *HistogramValue, *StateSetValue, *InfoValue or *SummaryValue depending
+	// on the type of the MetricFamily.
+	Value Value
+	// Timestamp is nil if the MetricPoint has no timestamp.
+	Timestamp *time.Time
+}
+
+// Value is the value of a MetricPoint.
+type Value interface {
+	isValue()
+}
+
+// UnknownValue is the value of an unknown MetricPoint.
+type UnknownValue struct {
+	Value Number
+}
+
+// GaugeValue is the value of a gauge MetricPoint.
+type GaugeValue struct {
+	Value Number
+}
+
+// CounterValue is the value of a counter MetricPoint.
+type CounterValue struct {
+	Total    Number
+	Created  *time.Time
+	Exemplar *Exemplar
+}
+
+// HistogramValue is the value of a histogram or gauge histogram MetricPoint,
+// Count and Sum are the gcount and gsum of a gauge histogram.
+type HistogramValue struct {
+	// Sum is nil if the MetricPoint has no sum, Count must then be 0.
+	Sum     *Number
+	Count   uint64
+	Created *time.Time
+	Buckets []Bucket
+}
+
+// Bucket is a histogram bucket.
+type Bucket struct {
+	Count      uint64
+	UpperBound float64
+	Exemplar   *Exemplar
+}
+
+// StateSetValue is the value of a StateSet MetricPoint.
+type StateSetValue struct {
+	States []State
+}
+
+// State is a state of a StateSet.
+type State struct {
+	Enabled bool
+	Name    string
+}
+
+// InfoValue is the value of an info MetricPoint. The text format does not
+// tell the labels of the Metric from the info labels, so the info labels are
+// labels of the Metric in the model. FromProto moves the info labels of the
+// protobuf format to the Metric, and ToProto leaves them there.
+type InfoValue struct{}
+
+// SummaryValue is the value of a summary MetricPoint.
+type SummaryValue struct {
+	// Sum is nil if the MetricPoint has no sum.
+	Sum       *Number
+	Count     uint64
+	Created   *time.Time
+	Quantiles []Quantile
+}
+
+// Quantile is a summary quantile.
+type Quantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// Exemplar is an exemplar of a counter or a histogram bucket.
+type Exemplar struct {
+	Value float64
+	// Timestamp is nil if the exemplar has no timestamp.
+	Timestamp *time.Time
+	Labels    labels.Labels
+}
+
+func (*UnknownValue) isValue()   {}
+func (*GaugeValue) isValue()     {}
+func (*CounterValue) isValue()   {}
+func (*HistogramValue) isValue() {}
+func (*StateSetValue) isValue()  {}
+func (*InfoValue) isValue()      {}
+func (*SummaryValue) isValue()   {}
+
+// Number is a value which is either an integer or a float, as the int_value
+// and double_value fields of the protobuf format. Integers above MaxInt64,
+// e.g. large counter totals, are unsigned.
+type Number struct {
+	IsInt bool
+	Int   int64
+	// IsUint is set instead of IsInt for integers above MaxInt64.
+	IsUint bool
+	Uint   uint64
+	Float  float64
+}
+
+// IntNumber returns an integer Number.
+func IntNumber(i int64) Number {
+	return Number{IsInt: true, Int: i}
+}
+
+// UintNumber returns an integer Number, which is unsigned if it is above
+// MaxInt64.
+func UintNumber(u uint64) Number {
+	if u <= math.MaxInt64 {
+		return IntNumber(int64(u))
+	}
+	return Number{IsUint: true, Uint: u}
+}
+
+// FloatNumber returns a float Number.
+func FloatNumber(f float64) Number {
+	return Number{Float: f}
+}
+
+// Float64 returns the value of the Number as a float.
+func (n Number) Float64() float64 {
+	switch {
+	case n.IsInt:
+		return float64(n.Int)
+	case n.IsUint:
+		return float64(n.Uint)
+	}
+	return n.Float
+}
+
+// parseNumber parses a value of the text format, it is an integer unless it
+// has a fraction, an exponent or is not finite. Integers which do not fit in
+// 64 bits are rejected rather than rounded to a float.
+func parseNumber(s string) (Number, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return IntNumber(i), nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return UintNumber(u), nil
+	}
+	if isInteger(s) {
+		return Number{}, fmt.Errorf("integer %s does not fit in 64 bits", s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Number{}, err
+	}
+	return FloatNumber(f), nil
+}
+
+// isInteger returns whether the value is written as an integer.
+func isInteger(s string) bool {
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		s = s[1:]
+	}
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isExactFloat returns whether the value is read exactly as a float, which
+// most integers above 2^53 are not.
+func isExactFloat(s string) bool {
+	if !isInteger(s) {
+		return true
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return true
+	}
+	_, acc := new(big.Float).SetInt(n).Float64()
+	return acc == big.Exact
+}
+
+// lossFunc records a conversion of the metric family which lost information.
+type lossFunc func(mf *MetricFamily, format string, args ...interface{})
+
+// lossRecorder records the conversions which lost information, each one once.
+type lossRecorder struct {
+	list []string
+	seen map[string]bool
+}
+
+// add is a lossFunc.
+func (l *lossRecorder) add(mf *MetricFamily, format string, args ...interface{}) {
+	loss := fmt.Sprintf("metric family %q: ", mf.Name) + fmt.Sprintf(format, args...)
+	if l.seen == nil {
+		l.seen = make(map[string]bool)
+	}
+	if !l.seen[loss] {
+		l.seen[loss] = true
+		l.list = append(l.list, loss)
+	}
+}
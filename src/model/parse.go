@@ -0,0 +1,506 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+var _textMetricTypes = map[textparse.MetricType]MetricType{
+	textparse.MetricTypeUnknown:        MetricTypeUnknown,
+	textparse.MetricTypeGauge:          MetricTypeGauge,
+	textparse.MetricTypeCounter:        MetricTypeCounter,
+	textparse.MetricTypeStateset:       MetricTypeStateSet,
+	textparse.MetricTypeInfo:           MetricTypeInfo,
+	textparse.MetricTypeHistogram:      MetricTypeHistogram,
+	textparse.MetricTypeGaugeHistogram: MetricTypeGaugeHistogram,
+	textparse.MetricTypeSummary:        MetricTypeSummary,
+}
+
+// _suffixes are the suffixes of the sample names of each metric type, the
+// empty suffix is the sample named after the metric family.
+var _suffixes = map[MetricType][]string{
+	MetricTypeUnknown:        {""},
+	MetricTypeGauge:          {""},
+	MetricTypeCounter:        {"_total", "_created"},
+	MetricTypeStateSet:       {""},
+	MetricTypeInfo:           {"_info"},
+	MetricTypeHistogram:      {"_bucket", "_count", "_sum", "_created"},
+	MetricTypeGaugeHistogram: {"_bucket", "_gcount", "_gsum"},
+	MetricTypeSummary:        {"", "_count", "_sum", "_created"},
+}
+
+var _unescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\"`, `"`)
+
+var _allSuffixes = []string{"_total", "_created", "_info", "_bucket", "_count", "_sum", "_gcount", "_gsum"}
+
+// Parse parses an exposition in the OpenMetrics text format. Samples are
+// grouped into MetricPoints by Metric and timestamp, and the samples of
+// interleaved metric families are merged into the first one.
+//
+// Parse only checks what the model needs, it returns an error if the
+// exposition cannot be parsed or cannot be represented in the model, e.g. a
+// histogram bucket with a non integer count. Use the validator to check the
+// exposition against the rest of the OpenMetrics spec.
+//
+// It returns the conversions which lost information, e.g. integer quantile
+// or exemplar values which are rounded to floats.
+func Parse(b []byte) (*MetricSet, []string, error) {
+	p := &parser{
+		families: make(map[string]*MetricFamily),
+		metrics:  make(map[string]*Metric),
+		seen:     make(map[*MetricPoint]map[string]bool),
+		lines:    bytes.SplitAfter(b, []byte("\n")),
+	}
+	if err := p.parse(textparse.NewOpenMetricsParser(b)); err != nil {
+		return nil, nil, fmt.Errorf("line %d: %w", p.line, err)
+	}
+	return &MetricSet{MetricFamilies: p.ordered}, p.losses.list, nil
+}
+
+type parser struct {
+	ordered  []*MetricFamily
+	families map[string]*MetricFamily
+	// last is the metric family of the most recent entry.
+	last *MetricFamily
+	// metrics are the metrics by metric family name and labels.
+	metrics map[string]*Metric
+	// seen are the samples of each MetricPoint, a repeated sample starts a
+	// new MetricPoint.
+	seen map[*MetricPoint]map[string]bool
+	// lines are the lines of the exposition, every entry is one line.
+	lines [][]byte
+	line  int
+	// prometheus is set when parsing the Prometheus text format.
+	prometheus bool
+	// losses are the conversions which lost information.
+	losses lossRecorder
+}
+
+func (p *parser) parse(tp textparse.Parser) error {
+	for {
+		p.line++
+		if p.prometheus {
+			p.skipBlankLines()
+		}
+		et, err := tp.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch et {
+		case textparse.EntryType:
+			name, mt := tp.Type()
+			mn := string(name)
+			if p.prometheus && mt == textparse.MetricTypeCounter {
+				mn = p.prometheusCounterName(mn)
+			}
+			if err := p.setType(mn, _textMetricTypes[mt]); err != nil {
+				return err
+			}
+		case textparse.EntryHelp:
+			name, help := tp.Help()
+			mn := string(name)
+			if p.prometheus {
+				mn = p.prometheusHelpName(mn)
+			}
+			p.family(mn).Help = string(help)
+		case textparse.EntryUnit:
+			name, unit := tp.Unit()
+			p.family(string(name)).Unit = string(unit)
+		case textparse.EntrySeries:
+			if err := p.addSample(tp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// family returns the metric family with the name, and creates it if needed.
+func (p *parser) family(name string) *MetricFamily {
+	mf, ok := p.families[name]
+	if !ok {
+		mf = &MetricFamily{Name: name}
+		p.families[name] = mf
+		p.ordered = append(p.ordered, mf)
+	}
+	p.last = mf
+	return mf
+}
+
+func (p *parser) setType(name string, mt MetricType) error {
+	mf := p.family(name)
+	if mf.Type == mt {
+		return nil
+	}
+	if mf.Type != MetricTypeUnknown || len(mf.Metrics) > 0 {
+		return fmt.Errorf("type of metric family %q changed from %v to %v", name, mf.Type, mt)
+	}
+	mf.Type = mt
+	return nil
+}
+
+// resolve returns the metric family of the sample and the suffix of the
+// sample name.
+func (p *parser) resolve(mn string) (*MetricFamily, string, error) {
+	if mf := p.last; mf != nil {
+		for _, suffix := range _suffixes[mf.Type] {
+			if mn == mf.Name+suffix {
+				return mf, suffix, nil
+			}
+		}
+	}
+	if mf, ok := p.families[mn]; ok {
+		suffix := ""
+		if p.prometheus && mf.Type == MetricTypeCounter {
+			// Prometheus counters may be named without the _total suffix.
+			suffix = "_total"
+		}
+		if !hasSuffix(mf.Type, suffix) {
+			return nil, "", fmt.Errorf("sample name %q is not valid for metric family %q of type %v", mn, mn, mf.Type)
+		}
+		p.last = mf
+		return mf, suffix, nil
+	}
+	for _, suffix := range _allSuffixes {
+		if !strings.HasSuffix(mn, suffix) {
+			continue
+		}
+		if mf, ok := p.families[strings.TrimSuffix(mn, suffix)]; ok && hasSuffix(mf.Type, suffix) {
+			p.last = mf
+			return mf, suffix, nil
+		}
+	}
+	return p.family(mn), "", nil
+}
+
+func hasSuffix(mt MetricType, suffix string) bool {
+	for _, s := range _suffixes[mt] {
+		if s == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) addSample(tp textparse.Parser) error {
+	series, _, _ := tp.Series()
+	var lset labels.Labels
+	tp.Metric(&lset)
+	mn := lset.Get(labels.MetricName)
+	if p.prometheus {
+		// The Prometheus parser keeps the indentation in the metric name.
+		mn = strings.TrimLeft(mn, " \t")
+	}
+	mf, suffix, err := p.resolve(mn)
+	if err != nil {
+		return err
+	}
+
+	line := p.lines[p.line-1]
+	if p.prometheus {
+		// Prometheus text samples may be indented.
+		line = line[bytes.Index(line, series):]
+	}
+	fields, err := splitSample(line, series)
+	if err != nil {
+		return err
+	}
+	value, err := parseNumber(fields.value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %w", fields.value, err)
+	}
+	var ts *time.Time
+	if fields.timestamp != "" {
+		parse := parseTimestamp
+		if p.prometheus {
+			parse = parsePrometheusTimestamp
+		}
+		t, err := parse(fields.timestamp)
+		if err != nil {
+			return err
+		}
+		ts = &t
+	}
+
+	// The label which tells apart the samples of a MetricPoint.
+	var pointLabel string
+	switch {
+	case suffix == "_bucket":
+		pointLabel = labels.BucketLabel
+	case mf.Type == MetricTypeSummary && suffix == "":
+		pointLabel = "quantile"
+	case mf.Type == MetricTypeStateSet:
+		pointLabel = mf.Name
+	}
+	if pointLabel != "" && !lset.Has(pointLabel) {
+		return fmt.Errorf("sample %q of metric family %q has no %q label", mn, mf.Name, pointLabel)
+	}
+	mp := p.metricPoint(mf, lset.WithoutLabels(labels.MetricName, pointLabel), ts,
+		mn+"\xff"+lset.Get(pointLabel))
+	// Quantile and exemplar values are floats in the model.
+	if mf.Type == MetricTypeSummary && suffix == "" && !isExactFloat(fields.value) {
+		p.losses.add(mf, "integer quantile value %s is converted to a float", fields.value)
+	}
+	if fields.exemplarValue != "" && !isExactFloat(fields.exemplarValue) {
+		p.losses.add(mf, "integer exemplar value %s is converted to a float", fields.exemplarValue)
+	}
+
+	var e *Exemplar
+	if fields.exemplarValue != "" {
+		var pe exemplar.Exemplar
+		tp.Exemplar(&pe)
+		if e, err = parseExemplar(pe.Labels, fields.exemplarValue, fields.exemplarTimestamp); err != nil {
+			return err
+		}
+	}
+	return setValue(mf, mp, suffix, lset.Get(pointLabel), fields.value, value, e)
+}
+
+// metricPoint returns the MetricPoint of the sample. The sample is added to
+// the last MetricPoint of the Metric unless the timestamps differ or the
+// MetricPoint already has the sample.
+func (p *parser) metricPoint(mf *MetricFamily, lset labels.Labels, ts *time.Time, sample string) *MetricPoint {
+	key := mf.Name + lset.String()
+	m, ok := p.metrics[key]
+	if !ok {
+		m = &Metric{Labels: lset}
+		p.metrics[key] = m
+		mf.Metrics = append(mf.Metrics, m)
+	}
+	if n := len(m.MetricPoints); n > 0 {
+		mp := m.MetricPoints[n-1]
+		if equalTimestamps(mp.Timestamp, ts) && !p.seen[mp][sample] {
+			p.seen[mp][sample] = true
+			return mp
+		}
+	}
+	mp := &MetricPoint{Timestamp: ts}
+	m.MetricPoints = append(m.MetricPoints, mp)
+	p.seen[mp] = map[string]bool{sample: true}
+	return mp
+}
+
+func equalTimestamps(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// setValue sets the value of the sample in the MetricPoint, raw is the value
+// as written in the exposition.
+func setValue(mf *MetricFamily, mp *MetricPoint, suffix, pointLabelValue, raw string, value Number, e *Exemplar) error {
+	if e != nil && !(mf.Type == MetricTypeCounter && suffix == "_total") && suffix != "_bucket" {
+		return fmt.Errorf("sample of metric family %q of type %v cannot have an exemplar", mf.Name, mf.Type)
+	}
+	switch mf.Type {
+	case MetricTypeUnknown:
+		mp.Value = &UnknownValue{Value: value}
+	case MetricTypeGauge:
+		mp.Value = &GaugeValue{Value: value}
+	case MetricTypeCounter:
+		v, _ := mp.Value.(*CounterValue)
+		if v == nil {
+			v = &CounterValue{}
+			mp.Value = v
+		}
+		if suffix == "_created" {
+			return setCreated(&v.Created, raw)
+		}
+		v.Total, v.Exemplar = value, e
+	case MetricTypeHistogram, MetricTypeGaugeHistogram:
+		v, _ := mp.Value.(*HistogramValue)
+		if v == nil {
+			v = &HistogramValue{}
+			mp.Value = v
+		}
+		switch suffix {
+		case "_bucket":
+			le, err := strconv.ParseFloat(pointLabelValue, 64)
+			if err != nil {
+				return fmt.Errorf("invalid bucket threshold %q: %w", pointLabelValue, err)
+			}
+			count, err := toCount(value)
+			if err != nil {
+				return err
+			}
+			v.Buckets = append(v.Buckets, Bucket{Count: count, UpperBound: le, Exemplar: e})
+		case "_count", "_gcount":
+			count, err := toCount(value)
+			if err != nil {
+				return err
+			}
+			v.Count = count
+		case "_sum", "_gsum":
+			v.Sum = &value
+		case "_created":
+			return setCreated(&v.Created, raw)
+		}
+	case MetricTypeStateSet:
+		v, _ := mp.Value.(*StateSetValue)
+		if v == nil {
+			v = &StateSetValue{}
+			mp.Value = v
+		}
+		f := value.Float64()
+		if f != 0 && f != 1 {
+			return fmt.Errorf("invalid state value %v, expected 0 or 1", f)
+		}
+		v.States = append(v.States, State{Enabled: f == 1, Name: pointLabelValue})
+	case MetricTypeInfo:
+		mp.Value = &InfoValue{}
+	case MetricTypeSummary:
+		v, _ := mp.Value.(*SummaryValue)
+		if v == nil {
+			v = &SummaryValue{}
+			mp.Value = v
+		}
+		switch suffix {
+		case "":
+			q, err := strconv.ParseFloat(pointLabelValue, 64)
+			if err != nil {
+				return fmt.Errorf("invalid quantile %q: %w", pointLabelValue, err)
+			}
+			v.Quantiles = append(v.Quantiles, Quantile{Quantile: q, Value: value.Float64()})
+		case "_count":
+			count, err := toCount(value)
+			if err != nil {
+				return err
+			}
+			v.Count = count
+		case "_sum":
+			v.Sum = &value
+		case "_created":
+			return setCreated(&v.Created, raw)
+		}
+	}
+	return nil
+}
+
+func setCreated(created **time.Time, raw string) error {
+	t, err := parseTimestamp(raw)
+	if err != nil {
+		return err
+	}
+	*created = &t
+	return nil
+}
+
+// toCount converts a count, which may be written as a float, e.g. "17.0".
+func toCount(n Number) (uint64, error) {
+	switch {
+	case n.IsInt && n.Int >= 0:
+		return uint64(n.Int), nil
+	case n.IsUint:
+		return n.Uint, nil
+	case !n.IsInt && !n.IsUint && n.Float >= 0 && n.Float < math.MaxUint64 && n.Float == math.Trunc(n.Float):
+		return uint64(n.Float), nil
+	}
+	return 0, fmt.Errorf("invalid count %s, expected a non negative integer", FormatNumber(n))
+}
+
+func parseExemplar(lset labels.Labels, value, ts string) (*Exemplar, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exemplar value %q: %w", value, err)
+	}
+	// The exemplar label values are not unescaped by the parser.
+	for i, l := range lset {
+		lset[i].Value = _unescaper.Replace(l.Value)
+	}
+	e := &Exemplar{Value: f, Labels: lset}
+	if ts != "" {
+		t, err := parseTimestamp(ts)
+		if err != nil {
+			return nil, err
+		}
+		e.Timestamp = &t
+	}
+	return e, nil
+}
+
+// sampleFields are the lexical fields of a sample line following the
+// metric name and labels.
+type sampleFields struct {
+	value             string
+	timestamp         string
+	exemplarValue     string
+	exemplarTimestamp string
+}
+
+// splitSample splits the line of a sample whose metric name and labels are
+// series. Neither the value nor the timestamp contain spaces, so the first
+// " # " starts the exemplar, and its value follows the last "}".
+func splitSample(line, series []byte) (sampleFields, error) {
+	var res sampleFields
+	rest := strings.TrimSuffix(string(line[len(series):]), "\n")
+	var ex string
+	if i := strings.Index(rest, " # "); i >= 0 {
+		rest, ex = rest[:i], rest[i+len(" # "):]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return res, fmt.Errorf("invalid sample %q", line)
+	}
+	res.value = fields[0]
+	if len(fields) == 2 {
+		res.timestamp = fields[1]
+	}
+	if ex == "" {
+		return res, nil
+	}
+	fields = strings.Fields(ex[strings.LastIndexByte(ex, '}')+1:])
+	if len(fields) == 0 || len(fields) > 2 {
+		return res, fmt.Errorf("invalid exemplar %q", ex)
+	}
+	res.exemplarValue = fields[0]
+	if len(fields) == 2 {
+		res.exemplarTimestamp = fields[1]
+	}
+	return res, nil
+}
+
+// parseTimestamp parses a timestamp in seconds, decimal timestamps are
+// parsed exactly up to nanoseconds. Timestamps beyond the range of int64
+// seconds are clamped.
+func parseTimestamp(s string) (time.Time, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", s, err)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: not a finite number", s)
+	}
+	sec, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		sec, frac = s[:i], s[i+1:]
+	}
+	secs, errSec := strconv.ParseInt(sec, 10, 64)
+	nsecs, errFrac := strconv.ParseInt((frac + "000000000")[:9], 10, 64)
+	if errSec != nil || errFrac != nil || len(frac) > 9 {
+		// Exponents and excess precision go through floats.
+		fsec, ffrac := math.Modf(f)
+		switch {
+		case fsec >= math.MaxInt64:
+			return time.Unix(math.MaxInt64, 0).UTC(), nil
+		case fsec <= math.MinInt64:
+			return time.Unix(math.MinInt64, 0).UTC(), nil
+		}
+		return time.Unix(int64(fsec), int64(ffrac*float64(time.Second))).UTC(), nil
+	}
+	if strings.HasPrefix(s, "-") {
+		nsecs = -nsecs
+	}
+	return time.Unix(secs, nsecs).UTC(), nil
+}
@@ -0,0 +1,233 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ms, _, err := Parse([]byte(`# TYPE a_seconds counter
+# UNIT a_seconds seconds
+# HELP a_seconds help
+a_seconds_total{b="1"} 1 # {trace_id="x"} 0.5 123.25
+a_seconds_created{b="1"} 1.5e9
+a_seconds_total{b="2"} 2.0 10
+a_seconds_total{b="2"} 3 20
+# TYPE b histogram
+b_bucket{le="1"} 0
+b_bucket{le="+Inf"} 2
+b_count 2
+b_sum 1.5
+# TYPE c stateset
+c{c="x"} 1
+c{c="y"} 0
+# TYPE d summary
+d{quantile="0.5"} 1
+d_count 3
+# TYPE e info
+e_info{f="g"} 1
+f -1
+# EOF
+`))
+	require.NoError(t, err)
+
+	ts := func(sec, nsec int64) *time.Time {
+		t := time.Unix(sec, nsec).UTC()
+		return &t
+	}
+	sum := FloatNumber(1.5)
+	require.Equal(t, &MetricSet{MetricFamilies: []*MetricFamily{
+		{
+			Name: "a_seconds",
+			Type: MetricTypeCounter,
+			Unit: "seconds",
+			Help: "help",
+			Metrics: []*Metric{
+				{
+					Labels: labels.FromStrings("b", "1"),
+					MetricPoints: []*MetricPoint{{Value: &CounterValue{
+						Total:   IntNumber(1),
+						Created: ts(1.5e9, 0),
+						Exemplar: &Exemplar{
+							Value:     0.5,
+							Timestamp: ts(123, 250000000),
+							Labels:    labels.FromStrings("trace_id", "x"),
+						},
+					}}},
+				},
+				{
+					Labels: labels.FromStrings("b", "2"),
+					MetricPoints: []*MetricPoint{
+						{Value: &CounterValue{Total: FloatNumber(2)}, Timestamp: ts(10, 0)},
+						{Value: &CounterValue{Total: IntNumber(3)}, Timestamp: ts(20, 0)},
+					},
+				},
+			},
+		},
+		{
+			Name: "b",
+			Type: MetricTypeHistogram,
+			Metrics: []*Metric{{
+				Labels: labels.Labels{},
+				MetricPoints: []*MetricPoint{{Value: &HistogramValue{
+					Sum:     &sum,
+					Count:   2,
+					Buckets: []Bucket{{Count: 0, UpperBound: 1}, {Count: 2, UpperBound: math.Inf(1)}},
+				}}},
+			}},
+		},
+		{
+			Name: "c",
+			Type: MetricTypeStateSet,
+			Metrics: []*Metric{{
+				Labels: labels.Labels{},
+				MetricPoints: []*MetricPoint{{Value: &StateSetValue{
+					States: []State{{Enabled: true, Name: "x"}, {Enabled: false, Name: "y"}},
+				}}},
+			}},
+		},
+		{
+			Name: "d",
+			Type: MetricTypeSummary,
+			Metrics: []*Metric{{
+				Labels: labels.Labels{},
+				MetricPoints: []*MetricPoint{{Value: &SummaryValue{
+					Count:     3,
+					Quantiles: []Quantile{{Quantile: 0.5, Value: 1}},
+				}}},
+			}},
+		},
+		{
+			Name: "e",
+			Type: MetricTypeInfo,
+			Metrics: []*Metric{{
+				Labels:       labels.FromStrings("f", "g"),
+				MetricPoints: []*MetricPoint{{Value: &InfoValue{}}},
+			}},
+		},
+		{
+			Name: "f",
+			Metrics: []*Metric{{
+				Labels:       labels.Labels{},
+				MetricPoints: []*MetricPoint{{Value: &UnknownValue{Value: IntNumber(-1)}}},
+			}},
+		},
+	}}, ms)
+}
+
+func TestParseLargeIntegers(t *testing.T) {
+	ms, _, err := Parse([]byte(`# TYPE a counter
+a_total 9223372036854775808
+# TYPE b histogram
+b_bucket{le="+Inf"} 18446744073709551615
+b_count 18446744073709551615
+b_sum 18446744073709551615
+# TYPE c gauge
+c -9223372036854775808
+# EOF
+`))
+	require.NoError(t, err)
+	require.Equal(t, &CounterValue{Total: UintNumber(1 << 63)}, ms.MetricFamilies[0].Metrics[0].MetricPoints[0].Value)
+	sum := UintNumber(math.MaxUint64)
+	require.Equal(t, &HistogramValue{
+		Sum:     &sum,
+		Count:   math.MaxUint64,
+		Buckets: []Bucket{{Count: math.MaxUint64, UpperBound: math.Inf(1)}},
+	}, ms.MetricFamilies[1].Metrics[0].MetricPoints[0].Value)
+	require.Equal(t, &GaugeValue{Value: IntNumber(math.MinInt64)}, ms.MetricFamilies[2].Metrics[0].MetricPoints[0].Value)
+	require.Equal(t, "18446744073709551615", FormatNumber(sum))
+}
+
+func TestParseLosses(t *testing.T) {
+	_, losses, err := Parse([]byte(`# TYPE a summary
+a{quantile="0.5"} 9007199254740993
+a{quantile="0.9"} 9007199254740994
+a_count 1
+# TYPE b counter
+b_total 9007199254740993 # {} 9007199254740993
+# EOF
+`))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`metric family "a": integer quantile value 9007199254740993 is converted to a float`,
+		`metric family "b": integer exemplar value 9007199254740993 is converted to a float`,
+	}, losses)
+}
+
+func TestParseInterleaved(t *testing.T) {
+	ms, _, err := Parse([]byte(`# TYPE a gauge
+a{b="1"} 1
+# TYPE c gauge
+c 1
+a{b="2"} 2
+# EOF
+`))
+	require.NoError(t, err)
+	require.Len(t, ms.MetricFamilies, 2)
+	require.Equal(t, "a", ms.MetricFamilies[0].Name)
+	require.Len(t, ms.MetricFamilies[0].Metrics, 2)
+}
+
+func TestParseError(t *testing.T) {
+	tcs := []struct {
+		name        string
+		input       string
+		expectedErr string
+	}{
+		{
+			name:        "parse_error",
+			input:       "a 1\n",
+			expectedErr: "line 2: data does not end with # EOF",
+		},
+		{
+			name:        "invalid_suffix",
+			input:       "# TYPE a counter\na 1\n# EOF\n",
+			expectedErr: `line 2: sample name "a" is not valid for metric family "a" of type counter`,
+		},
+		{
+			name:        "non_integer_count",
+			input:       "# TYPE a histogram\na_bucket{le=\"+Inf\"} 1.5\n# EOF\n",
+			expectedErr: "line 2: invalid count 1.5, expected a non negative integer",
+		},
+		{
+			name:        "integer_above_uint64",
+			input:       "# TYPE a counter\na_total 18446744073709551616\n# EOF\n",
+			expectedErr: `line 2: invalid value "18446744073709551616": integer 18446744073709551616 does not fit in 64 bits`,
+		},
+		{
+			name:        "integer_below_int64",
+			input:       "a -9223372036854775809\n# EOF\n",
+			expectedErr: `line 1: invalid value "-9223372036854775809": integer -9223372036854775809 does not fit in 64 bits`,
+		},
+		{
+			name:        "nan_created",
+			input:       "# TYPE a counter\na_total 1\na_created NaN\n# EOF\n",
+			expectedErr: `line 3: invalid timestamp "NaN": not a finite number`,
+		},
+		{
+			name:        "infinite_created",
+			input:       "# TYPE a counter\na_total 1\na_created -Inf\n# EOF\n",
+			expectedErr: `line 3: invalid timestamp "-Inf": not a finite number`,
+		},
+		{
+			name:        "missing_bucket_label",
+			input:       "# TYPE a histogram\na_bucket 1\n# EOF\n",
+			expectedErr: `line 2: sample "a_bucket" of metric family "a" has no "le" label`,
+		},
+		{
+			name:        "type_changed",
+			input:       "a 1\n# TYPE a gauge\n# EOF\n",
+			expectedErr: `line 2: type of metric family "a" changed from unknown to gauge`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := Parse([]byte(tc.input))
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
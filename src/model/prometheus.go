@@ -0,0 +1,202 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// _prometheusTypes are the types metric families are exposed as in the
+// Prometheus text format.
+var _prometheusTypes = map[MetricType]string{
+	MetricTypeUnknown:        "untyped",
+	MetricTypeGauge:          "gauge",
+	MetricTypeCounter:        "counter",
+	MetricTypeStateSet:       "gauge",
+	MetricTypeInfo:           "gauge",
+	MetricTypeHistogram:      "histogram",
+	MetricTypeGaugeHistogram: "gauge",
+	MetricTypeSummary:        "summary",
+}
+
+var _helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// ParsePrometheusText parses an exposition in the Prometheus text format
+// 0.0.4 like Parse does. Untyped metric families are unknown, counters are
+// named without the _total suffix and timestamps are in milliseconds.
+func ParsePrometheusText(b []byte) (*MetricSet, []string, error) {
+	p := &parser{
+		families:   make(map[string]*MetricFamily),
+		metrics:    make(map[string]*Metric),
+		seen:       make(map[*MetricPoint]map[string]bool),
+		lines:      bytes.SplitAfter(b, []byte("\n")),
+		prometheus: true,
+	}
+	if err := p.parse(textparse.NewPromParser(b)); err != nil {
+		return nil, nil, fmt.Errorf("line %d: %w", p.line, err)
+	}
+	return &MetricSet{MetricFamilies: p.ordered}, p.losses.list, nil
+}
+
+// skipBlankLines moves past the blank lines the Prometheus parser skips.
+func (p *parser) skipBlankLines() {
+	for p.line <= len(p.lines) && len(bytes.TrimSpace(p.lines[p.line-1])) == 0 {
+		p.line++
+	}
+}
+
+// prometheusCounterName returns the metric family name of a counter TYPE.
+// A metric family created by a preceding HELP with the _total suffix is
+// renamed.
+func (p *parser) prometheusCounterName(name string) string {
+	mn := strings.TrimSuffix(name, "_total")
+	if mn == name {
+		return name
+	}
+	mf, ok := p.families[name]
+	if _, clash := p.families[mn]; ok && !clash && mf.Type == MetricTypeUnknown && len(mf.Metrics) == 0 {
+		delete(p.families, name)
+		mf.Name = mn
+		p.families[mn] = mf
+	}
+	return mn
+}
+
+// prometheusHelpName returns the metric family name of a HELP, which has the
+// _total suffix for counters.
+func (p *parser) prometheusHelpName(name string) string {
+	mn := strings.TrimSuffix(name, "_total")
+	if mf, ok := p.families[mn]; ok && mn != name && mf.Type == MetricTypeCounter {
+		return mn
+	}
+	return name
+}
+
+// parsePrometheusTimestamp parses a timestamp in milliseconds.
+func parsePrometheusTimestamp(s string) (time.Time, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", s, err)
+	}
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond)).UTC(), nil
+}
+
+// EncodePrometheusText writes the MetricSet in the Prometheus text format
+// 0.0.4. Metric families are exposed as the Prometheus client libraries do:
+// unknown ones are untyped, and statesets, infos and gauge histograms are
+// gauges.
+//
+// It returns the conversions which lost information, e.g. dropped units,
+// created timestamps and exemplars. Like Encode, it only checks what the text
+// format needs, and writes nothing if a name cannot be written or a
+// MetricPoint cannot be exposed.
+func EncodePrometheusText(w io.Writer, ms *MetricSet) ([]string, error) {
+	var (
+		buf     bytes.Buffer
+		losses  lossRecorder
+		written = make(map[string]bool, len(ms.MetricFamilies))
+	)
+	for _, mf := range ms.MetricFamilies {
+		name := prometheusName(mf)
+		if written[name] {
+			return nil, fmt.Errorf("metric family %q is in the MetricSet twice", name)
+		}
+		written[name] = true
+		if err := encodePrometheusMetricFamily(&buf, mf, losses.add); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return losses.list, nil
+}
+
+// prometheusName returns the name of the metric family in the TYPE and HELP
+// lines, which has the suffix of the samples for counters and infos.
+func prometheusName(mf *MetricFamily) string {
+	switch mf.Type {
+	case MetricTypeCounter:
+		return mf.Name + "_total"
+	case MetricTypeInfo:
+		return mf.Name + "_info"
+	}
+	return mf.Name
+}
+
+func encodePrometheusMetricFamily(buf *bytes.Buffer, mf *MetricFamily, lossy lossFunc) error {
+	if err := checkMetricFamilyName(mf); err != nil {
+		return err
+	}
+	name := prometheusName(mf)
+	typ := _prometheusTypes[mf.Type]
+	if typ != mf.Type.String() && mf.Type != MetricTypeUnknown {
+		lossy(mf, "%v is converted to %s", mf.Type, typ)
+	}
+	if mf.Unit != "" {
+		lossy(mf, "unit %q is dropped", mf.Unit)
+	}
+	if mf.Help != "" {
+		fmt.Fprintf(buf, "# HELP %s %s\n", name, _helpEscaper.Replace(mf.Help))
+	}
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, typ)
+
+	for _, m := range mf.Metrics {
+		for _, mp := range m.MetricPoints {
+			samples, err := metricPointSamples(mf, mp)
+			if err != nil {
+				return err
+			}
+			for _, s := range samples {
+				if s.suffix == "_created" {
+					lossy(mf, "created timestamps are dropped")
+					continue
+				}
+				sampleLset := append(append(labels.Labels{}, m.Labels...), s.labels...)
+				if err := checkLabelNames(mf, sampleLset); err != nil {
+					return err
+				}
+				if s.exemplar != nil {
+					lossy(mf, "exemplars are dropped")
+				}
+				if !isExactFloat(s.text) {
+					lossy(mf, "integer values above 2^53 are not exact")
+				}
+				buf.WriteString(mf.Name + s.suffix)
+				writeLabels(buf, sampleLset)
+				buf.WriteString(" " + s.text)
+				if t := mp.Timestamp; t != nil {
+					buf.WriteString(" " + strconv.FormatInt(prometheusTimestamp(mf, *t, lossy), 10))
+				}
+				buf.WriteString("\n")
+			}
+		}
+	}
+	return nil
+}
+
+// prometheusTimestamp returns the timestamp in milliseconds, clamped to the
+// range of int64.
+func prometheusTimestamp(mf *MetricFamily, t time.Time, lossy lossFunc) int64 {
+	sec := t.Unix()
+	switch {
+	case sec > math.MaxInt64/1000:
+		lossy(mf, "timestamps are clamped to the range of int64 milliseconds")
+		return math.MaxInt64
+	case sec < math.MinInt64/1000:
+		lossy(mf, "timestamps are clamped to the range of int64 milliseconds")
+		return math.MinInt64
+	}
+	if t.Nanosecond()%int(time.Millisecond) != 0 {
+		lossy(mf, "timestamps are truncated to milliseconds")
+	}
+	return sec*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/OpenObservability/OpenMetrics/src/validator"
+)
+
+func TestParsePrometheusText(t *testing.T) {
+	ms, _, err := ParsePrometheusText([]byte(`# HELP a_total help with \\ and \n
+# TYPE a_total counter
+a_total{b="c"} 1 1500
+
+  # TYPE d untyped
+  d 2.5
+# TYPE e counter
+e 3
+`))
+	require.NoError(t, err)
+	ts := time.Unix(1, 500000000).UTC()
+	require.Equal(t, &MetricSet{MetricFamilies: []*MetricFamily{
+		{
+			Name: "a",
+			Type: MetricTypeCounter,
+			Help: "help with \\ and \n",
+			Metrics: []*Metric{{
+				Labels:       labels.FromStrings("b", "c"),
+				MetricPoints: []*MetricPoint{{Value: &CounterValue{Total: IntNumber(1)}, Timestamp: &ts}},
+			}},
+		},
+		{
+			Name: "d",
+			Metrics: []*Metric{{
+				Labels:       labels.Labels{},
+				MetricPoints: []*MetricPoint{{Value: &UnknownValue{Value: FloatNumber(2.5)}}},
+			}},
+		},
+		{
+			Name: "e",
+			Type: MetricTypeCounter,
+			Metrics: []*Metric{{
+				Labels:       labels.Labels{},
+				MetricPoints: []*MetricPoint{{Value: &CounterValue{Total: IntNumber(3)}}},
+			}},
+		},
+	}}, ms)
+
+	_, _, err = ParsePrometheusText([]byte("a 1\n\n\na{\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 4: ")
+}
+
+func TestEncodePrometheusText(t *testing.T) {
+	ms, _, err := Parse([]byte(`# TYPE a_seconds counter
+# UNIT a_seconds seconds
+# HELP a_seconds help with \\ and \"
+a_seconds_total{b="c"} 1 1.0005 # {trace_id="x"} 1.0
+a_seconds_created{b="c"} 1 1.0005
+# TYPE d stateset
+d{d="x"} 1
+# TYPE e info
+e_info{f="g"} 1
+# TYPE h gaugehistogram
+h_bucket{le="+Inf"} 2
+h_gcount 2
+h_gsum 3
+# TYPE i unknown
+i 9007199254740993
+# EOF
+`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	losses, err := EncodePrometheusText(&buf, ms)
+	require.NoError(t, err)
+	require.Equal(t, `# HELP a_seconds_total help with \\ and "
+# TYPE a_seconds_total counter
+a_seconds_total{b="c"} 1 1000
+# TYPE d gauge
+d{d="x"} 1
+# TYPE e_info gauge
+e_info{f="g"} 1
+# TYPE h gauge
+h_bucket{le="+Inf"} 2
+h_gcount 2
+h_gsum 3
+# TYPE i untyped
+i 9007199254740993
+`, buf.String())
+	require.Equal(t, []string{
+		`metric family "a_seconds": unit "seconds" is dropped`,
+		`metric family "a_seconds": exemplars are dropped`,
+		`metric family "a_seconds": timestamps are truncated to milliseconds`,
+		`metric family "a_seconds": created timestamps are dropped`,
+		`metric family "d": stateset is converted to gauge`,
+		`metric family "e": info is converted to gauge`,
+		`metric family "h": gaugehistogram is converted to gauge`,
+		`metric family "i": integer values above 2^53 are not exact`,
+	}, losses)
+
+	roundtripped, _, err := ParsePrometheusText(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "a_seconds", roundtripped.MetricFamilies[0].Name)
+	require.Equal(t, MetricTypeCounter, roundtripped.MetricFamilies[0].Type)
+}
+
+func TestEncodePrometheusTextInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := EncodePrometheusText(&buf, &MetricSet{MetricFamilies: []*MetricFamily{
+		{Name: "a", Type: MetricTypeCounter},
+		{Name: "a_total", Type: MetricTypeGauge},
+	}})
+	require.EqualError(t, err, `metric family "a_total" is in the MetricSet twice`)
+	require.Zero(t, buf.Len())
+
+	_, err = EncodePrometheusText(&buf, &MetricSet{MetricFamilies: []*MetricFamily{{
+		Name: "a",
+		Type: MetricTypeGauge,
+		Metrics: []*Metric{{
+			Labels:       labels.Labels{{Name: "b-c", Value: "1"}},
+			MetricPoints: []*MetricPoint{{Value: &GaugeValue{}}},
+		}},
+	}}})
+	require.EqualError(t, err, `invalid label name "b-c" in metric family "a"`)
+	require.Zero(t, buf.Len())
+}
+
+// TestPrometheusTextRoundtrip makes sure that the expositions of the test
+// suite which should parse can be converted to valid Prometheus text and
+// parsed back.
+func TestPrometheusTextRoundtrip(t *testing.T) {
+	dirs, err := filepath.Glob("../../tests/testdata/parsers/*")
+	require.NoError(t, err)
+	for _, dir := range dirs {
+		if !shouldParse(t, dir) {
+			continue
+		}
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			b, err := ioutil.ReadFile(filepath.Join(dir, "metrics"))
+			require.NoError(t, err)
+			ms, _, err := Parse(b)
+			require.NoError(t, err)
+
+			var encoded bytes.Buffer
+			_, err = EncodePrometheusText(&encoded, ms)
+			require.NoError(t, err)
+			v := validator.NewValidator(validator.ErrorLevelMust, validator.WithFormat(validator.FormatPrometheusText))
+			require.NoError(t, v.Validate(encoded.Bytes()), "invalid encoding:\n%s", encoded.String())
+			_, _, err = ParsePrometheusText(encoded.Bytes())
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/OpenObservability/OpenMetrics/src/proto/openmetrics"
+)
+
+// ToProto converts the MetricSet to the protobuf format. It returns the
+// conversions which lost information, e.g. integer gauge values above
+// MaxInt64 which are converted to floats.
+func ToProto(ms *MetricSet) (*openmetrics.MetricSet, []string, error) {
+	var (
+		res    = &openmetrics.MetricSet{}
+		losses lossRecorder
+	)
+	for _, mf := range ms.MetricFamilies {
+		pmf := &openmetrics.MetricFamily{
+			Name: mf.Name,
+			Type: openmetrics.MetricType(mf.Type),
+			Unit: mf.Unit,
+			Help: mf.Help,
+		}
+		for _, m := range mf.Metrics {
+			pm := &openmetrics.Metric{Labels: protoLabels(m.Labels)}
+			for _, mp := range m.MetricPoints {
+				pmp, err := protoMetricPoint(mf, mp, losses.add)
+				if err != nil {
+					return nil, nil, err
+				}
+				pm.MetricPoints = append(pm.MetricPoints, pmp)
+			}
+			pmf.Metrics = append(pmf.Metrics, pm)
+		}
+		res.MetricFamilies = append(res.MetricFamilies, pmf)
+	}
+	return res, losses.list, nil
+}
+
+func protoMetricPoint(mf *MetricFamily, mp *MetricPoint, lossy lossFunc) (*openmetrics.MetricPoint, error) {
+	res := &openmetrics.MetricPoint{Timestamp: protoTimestamp(mp.Timestamp)}
+	switch v := mp.Value.(type) {
+	case *UnknownValue:
+		if mf.Type != MetricTypeUnknown {
+			break
+		}
+		pv := &openmetrics.UnknownValue{}
+		if v.Value.IsInt {
+			pv.Value = &openmetrics.UnknownValue_IntValue{IntValue: v.Value.Int}
+		} else {
+			pv.Value = &openmetrics.UnknownValue_DoubleValue{DoubleValue: protoDouble(mf, v.Value, lossy)}
+		}
+		res.Value = &openmetrics.MetricPoint_UnknownValue{UnknownValue: pv}
+		return res, nil
+	case *GaugeValue:
+		if mf.Type != MetricTypeGauge {
+			break
+		}
+		pv := &openmetrics.GaugeValue{}
+		if v.Value.IsInt {
+			pv.Value = &openmetrics.GaugeValue_IntValue{IntValue: v.Value.Int}
+		} else {
+			pv.Value = &openmetrics.GaugeValue_DoubleValue{DoubleValue: protoDouble(mf, v.Value, lossy)}
+		}
+		res.Value = &openmetrics.MetricPoint_GaugeValue{GaugeValue: pv}
+		return res, nil
+	case *CounterValue:
+		if mf.Type != MetricTypeCounter {
+			break
+		}
+		pv := &openmetrics.CounterValue{
+			Created:  protoTimestamp(v.Created),
+			Exemplar: protoExemplar(v.Exemplar),
+		}
+		// The integer total is unsigned.
+		switch {
+		case v.Total.IsInt && v.Total.Int >= 0:
+			pv.Total = &openmetrics.CounterValue_IntValue{IntValue: uint64(v.Total.Int)}
+		case v.Total.IsUint:
+			pv.Total = &openmetrics.CounterValue_IntValue{IntValue: v.Total.Uint}
+		default:
+			pv.Total = &openmetrics.CounterValue_DoubleValue{DoubleValue: protoDouble(mf, v.Total, lossy)}
+		}
+		res.Value = &openmetrics.MetricPoint_CounterValue{CounterValue: pv}
+		return res, nil
+	case *HistogramValue:
+		if mf.Type != MetricTypeHistogram && mf.Type != MetricTypeGaugeHistogram {
+			break
+		}
+		pv := &openmetrics.HistogramValue{Count: v.Count, Created: protoTimestamp(v.Created)}
+		if v.Sum != nil {
+			if v.Sum.IsInt {
+				pv.Sum = &openmetrics.HistogramValue_IntValue{IntValue: v.Sum.Int}
+			} else {
+				pv.Sum = &openmetrics.HistogramValue_DoubleValue{DoubleValue: protoDouble(mf, *v.Sum, lossy)}
+			}
+		}
+		for _, b := range v.Buckets {
+			pv.Buckets = append(pv.Buckets, &openmetrics.HistogramValue_Bucket{
+				Count:      b.Count,
+				UpperBound: b.UpperBound,
+				Exemplar:   protoExemplar(b.Exemplar),
+			})
+		}
+		res.Value = &openmetrics.MetricPoint_HistogramValue{HistogramValue: pv}
+		return res, nil
+	case *StateSetValue:
+		if mf.Type != MetricTypeStateSet {
+			break
+		}
+		pv := &openmetrics.StateSetValue{}
+		for _, s := range v.States {
+			pv.States = append(pv.States, &openmetrics.StateSetValue_State{Enabled: s.Enabled, Name: s.Name})
+		}
+		res.Value = &openmetrics.MetricPoint_StateSetValue{StateSetValue: pv}
+		return res, nil
+	case *InfoValue:
+		if mf.Type != MetricTypeInfo {
+			break
+		}
+		res.Value = &openmetrics.MetricPoint_InfoValue{InfoValue: &openmetrics.InfoValue{}}
+		return res, nil
+	case *SummaryValue:
+		if mf.Type != MetricTypeSummary {
+			break
+		}
+		pv := &openmetrics.SummaryValue{Count: v.Count, Created: protoTimestamp(v.Created)}
+		if v.Sum != nil {
+			if v.Sum.IsInt {
+				pv.Sum = &openmetrics.SummaryValue_IntValue{IntValue: v.Sum.Int}
+			} else {
+				pv.Sum = &openmetrics.SummaryValue_DoubleValue{DoubleValue: protoDouble(mf, *v.Sum, lossy)}
+			}
+		}
+		for _, q := range v.Quantiles {
+			pv.Quantile = append(pv.Quantile, &openmetrics.SummaryValue_Quantile{Quantile: q.Quantile, Value: q.Value})
+		}
+		res.Value = &openmetrics.MetricPoint_SummaryValue{SummaryValue: pv}
+		return res, nil
+	}
+	return nil, fmt.Errorf("MetricPoint value %T of metric family %q does not match its type %v", mp.Value, mf.Name, mf.Type)
+}
+
+// protoDouble returns the Number as a double, for the integers which do not
+// fit in the int_value fields.
+func protoDouble(mf *MetricFamily, n Number, lossy lossFunc) float64 {
+	if s := FormatNumber(n); (n.IsInt || n.IsUint) && !isExactFloat(s) {
+		lossy(mf, "integer value %s is converted to a float", s)
+	}
+	return n.Float64()
+}
+
+func protoLabels(lset labels.Labels) []*openmetrics.Label {
+	var res []*openmetrics.Label
+	for _, l := range lset {
+		res = append(res, &openmetrics.Label{Name: l.Name, Value: l.Value})
+	}
+	return res
+}
+
+func protoTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func protoExemplar(e *Exemplar) *openmetrics.Exemplar {
+	if e == nil {
+		return nil
+	}
+	return &openmetrics.Exemplar{
+		Value:     e.Value,
+		Timestamp: protoTimestamp(e.Timestamp),
+		Label:     protoLabels(e.Labels),
+	}
+}
+
+// FromProto converts a MetricSet of the protobuf format, the model holds all
+// its values exactly.
+func FromProto(pms *openmetrics.MetricSet) (*MetricSet, error) {
+	res := &MetricSet{}
+	for _, pmf := range pms.GetMetricFamilies() {
+		mf := &MetricFamily{
+			Name: pmf.GetName(),
+			Type: MetricType(pmf.GetType()),
+			Unit: pmf.GetUnit(),
+			Help: pmf.GetHelp(),
+		}
+		if _, ok := _metricTypeNames[mf.Type]; !ok {
+			return nil, fmt.Errorf("unknown type %v of metric family %q", pmf.GetType(), mf.Name)
+		}
+		for _, pm := range pmf.GetMetrics() {
+			metrics, err := modelMetrics(mf, pm)
+			if err != nil {
+				return nil, err
+			}
+			mf.Metrics = append(mf.Metrics, metrics...)
+		}
+		res.MetricFamilies = append(res.MetricFamilies, mf)
+	}
+	return res, nil
+}
+
+// modelMetrics converts a Metric. The info labels are labels of the Metric in
+// the model, so the MetricPoints of a Metric are split into several Metrics if
+// their info labels differ.
+func modelMetrics(mf *MetricFamily, pm *openmetrics.Metric) ([]*Metric, error) {
+	var (
+		res      []*Metric
+		byLabels = make(map[string]*Metric)
+	)
+	for _, pmp := range pm.GetMetricPoints() {
+		mp, err := modelMetricPoint(mf, pmp)
+		if err != nil {
+			return nil, err
+		}
+		lset := modelLabels(pm.GetLabels())
+		if info := pmp.GetInfoValue(); info != nil {
+			lset = append(lset, modelLabels(info.GetInfo())...)
+		}
+		m, ok := byLabels[lset.String()]
+		if !ok {
+			m = &Metric{Labels: lset}
+			byLabels[lset.String()] = m
+			res = append(res, m)
+		}
+		m.MetricPoints = append(m.MetricPoints, mp)
+	}
+	if len(res) == 0 {
+		return []*Metric{{Labels: modelLabels(pm.GetLabels())}}, nil
+	}
+	return res, nil
+}
+
+func modelMetricPoint(mf *MetricFamily, pmp *openmetrics.MetricPoint) (*MetricPoint, error) {
+	res := &MetricPoint{Timestamp: modelTimestamp(pmp.GetTimestamp())}
+	switch v := pmp.GetValue().(type) {
+	case *openmetrics.MetricPoint_UnknownValue:
+		n, ok := modelNumber(v.UnknownValue.GetValue())
+		if mf.Type != MetricTypeUnknown || !ok {
+			break
+		}
+		res.Value = &UnknownValue{Value: n}
+		return res, nil
+	case *openmetrics.MetricPoint_GaugeValue:
+		n, ok := modelNumber(v.GaugeValue.GetValue())
+		if mf.Type != MetricTypeGauge || !ok {
+			break
+		}
+		res.Value = &GaugeValue{Value: n}
+		return res, nil
+	case *openmetrics.MetricPoint_CounterValue:
+		cv := v.CounterValue
+		var total Number
+		switch t := cv.GetTotal().(type) {
+		case *openmetrics.CounterValue_DoubleValue:
+			total = FloatNumber(t.DoubleValue)
+		case *openmetrics.CounterValue_IntValue:
+			total = UintNumber(t.IntValue)
+		default:
+			return nil, fmt.Errorf("counter MetricPoint of metric family %q has no total", mf.Name)
+		}
+		if mf.Type != MetricTypeCounter {
+			break
+		}
+		res.Value = &CounterValue{
+			Total:    total,
+			Created:  modelTimestamp(cv.GetCreated()),
+			Exemplar: modelExemplar(cv.GetExemplar()),
+		}
+		return res, nil
+	case *openmetrics.MetricPoint_HistogramValue:
+		if mf.Type != MetricTypeHistogram && mf.Type != MetricTypeGaugeHistogram {
+			break
+		}
+		hv := v.HistogramValue
+		value := &HistogramValue{Count: hv.GetCount(), Created: modelTimestamp(hv.GetCreated())}
+		if n, ok := modelNumber(hv.GetSum()); ok {
+			value.Sum = &n
+		}
+		for _, b := range hv.GetBuckets() {
+			value.Buckets = append(value.Buckets, Bucket{
+				Count:      b.GetCount(),
+				UpperBound: b.GetUpperBound(),
+				Exemplar:   modelExemplar(b.GetExemplar()),
+			})
+		}
+		res.Value = value
+		return res, nil
+	case *openmetrics.MetricPoint_StateSetValue:
+		if mf.Type != MetricTypeStateSet {
+			break
+		}
+		value := &StateSetValue{}
+		for _, s := range v.StateSetValue.GetStates() {
+			value.States = append(value.States, State{Enabled: s.GetEnabled(), Name: s.GetName()})
+		}
+		res.Value = value
+		return res, nil
+	case *openmetrics.MetricPoint_InfoValue:
+		if mf.Type != MetricTypeInfo {
+			break
+		}
+		res.Value = &InfoValue{}
+		return res, nil
+	case *openmetrics.MetricPoint_SummaryValue:
+		if mf.Type != MetricTypeSummary {
+			break
+		}
+		sv := v.SummaryValue
+		value := &SummaryValue{Count: sv.GetCount(), Created: modelTimestamp(sv.GetCreated())}
+		if n, ok := modelNumber(sv.GetSum()); ok {
+			value.Sum = &n
+		}
+		for _, q := range sv.GetQuantile() {
+			value.Quantiles = append(value.Quantiles, Quantile{Quantile: q.GetQuantile(), Value: q.GetValue()})
+		}
+		res.Value = value
+		return res, nil
+	}
+	return nil, fmt.Errorf("MetricPoint value %T of metric family %q is missing or does not match its type %v",
+		pmp.GetValue(), mf.Name, mf.Type)
+}
+
+// modelNumber converts a number oneof, it returns false if it is not set.
+func modelNumber(value interface{}) (Number, bool) {
+	switch n := value.(type) {
+	case *openmetrics.UnknownValue_DoubleValue:
+		return FloatNumber(n.DoubleValue), true
+	case *openmetrics.UnknownValue_IntValue:
+		return IntNumber(n.IntValue), true
+	case *openmetrics.GaugeValue_DoubleValue:
+		return FloatNumber(n.DoubleValue), true
+	case *openmetrics.GaugeValue_IntValue:
+		return IntNumber(n.IntValue), true
+	case *openmetrics.HistogramValue_DoubleValue:
+		return FloatNumber(n.DoubleValue), true
+	case *openmetrics.HistogramValue_IntValue:
+		return IntNumber(n.IntValue), true
+	case *openmetrics.SummaryValue_DoubleValue:
+		return FloatNumber(n.DoubleValue), true
+	case *openmetrics.SummaryValue_IntValue:
+		return IntNumber(n.IntValue), true
+	}
+	return Number{}, false
+}
+
+func modelLabels(ls []*openmetrics.Label) labels.Labels {
+	res := make(labels.Labels, 0, len(ls))
+	for _, l := range ls {
+		res = append(res, labels.Label{Name: l.GetName(), Value: l.GetValue()})
+	}
+	return res
+}
+
+func modelTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func modelExemplar(e *openmetrics.Exemplar) *Exemplar {
+	if e == nil {
+		return nil
+	}
+	return &Exemplar{
+		Value:     e.GetValue(),
+		Timestamp: modelTimestamp(e.GetTimestamp()),
+		Labels:    modelLabels(e.GetLabel()),
+	}
+}
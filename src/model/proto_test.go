@@ -0,0 +1,132 @@
+package model
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/OpenObservability/OpenMetrics/src/proto/openmetrics"
+)
+
+// TestProtoRoundtrip makes sure that the expositions of the test suite which
+// should parse are the same once converted to protobuf and back.
+func TestProtoRoundtrip(t *testing.T) {
+	dirs, err := filepath.Glob("../../tests/testdata/parsers/*")
+	require.NoError(t, err)
+	for _, dir := range dirs {
+		if !shouldParse(t, dir) {
+			continue
+		}
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			b, err := ioutil.ReadFile(filepath.Join(dir, "metrics"))
+			require.NoError(t, err)
+			ms, _, err := Parse(b)
+			require.NoError(t, err)
+
+			pms, losses, err := ToProto(ms)
+			require.NoError(t, err)
+			require.Empty(t, losses)
+			converted, err := FromProto(pms)
+			require.NoError(t, err)
+
+			var encoded, reencoded bytes.Buffer
+			require.NoError(t, Encode(&encoded, ms))
+			require.NoError(t, Encode(&reencoded, converted))
+			require.Equal(t, encoded.String(), reencoded.String())
+		})
+	}
+}
+
+func TestToProtoLosses(t *testing.T) {
+	sum := UintNumber(1<<64 - 1)
+	ms := &MetricSet{MetricFamilies: []*MetricFamily{
+		{
+			Name: "a",
+			Type: MetricTypeGauge,
+			Metrics: []*Metric{
+				{Labels: labels.FromStrings("b", "1"), MetricPoints: []*MetricPoint{{Value: &GaugeValue{Value: UintNumber(1 << 63)}}}},
+				{Labels: labels.FromStrings("b", "2"), MetricPoints: []*MetricPoint{{Value: &GaugeValue{Value: UintNumber(1<<63 + 1)}}}},
+			},
+		},
+		{
+			Name:    "c",
+			Type:    MetricTypeHistogram,
+			Metrics: []*Metric{{MetricPoints: []*MetricPoint{{Value: &HistogramValue{Sum: &sum, Buckets: []Bucket{{UpperBound: math.Inf(1)}}}}}}},
+		},
+	}}
+	pms, losses, err := ToProto(ms)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`metric family "a": integer value 9223372036854775809 is converted to a float`,
+		`metric family "c": integer value 18446744073709551615 is converted to a float`,
+	}, losses)
+	require.Equal(t, float64(1<<63), pms.MetricFamilies[0].Metrics[0].MetricPoints[0].GetGaugeValue().GetDoubleValue())
+}
+
+func TestFromProto(t *testing.T) {
+	pms := &openmetrics.MetricSet{MetricFamilies: []*openmetrics.MetricFamily{{
+		Name: "a",
+		Type: openmetrics.MetricType_COUNTER,
+		Metrics: []*openmetrics.Metric{{
+			Labels: []*openmetrics.Label{{Name: "b", Value: "c"}},
+			MetricPoints: []*openmetrics.MetricPoint{
+				{Value: &openmetrics.MetricPoint_CounterValue{CounterValue: &openmetrics.CounterValue{
+					Total: &openmetrics.CounterValue_IntValue{IntValue: 1},
+				}}},
+				{Value: &openmetrics.MetricPoint_CounterValue{CounterValue: &openmetrics.CounterValue{
+					Total: &openmetrics.CounterValue_IntValue{IntValue: math.MaxUint64},
+				}}},
+			},
+		}},
+	}}}
+	ms, err := FromProto(pms)
+	require.NoError(t, err)
+	points := ms.MetricFamilies[0].Metrics[0].MetricPoints
+	require.Equal(t, &CounterValue{Total: IntNumber(1)}, points[0].Value)
+	require.Equal(t, &CounterValue{Total: UintNumber(math.MaxUint64)}, points[1].Value)
+
+	pms.MetricFamilies[0].Type = openmetrics.MetricType_GAUGE
+	_, err = FromProto(pms)
+	require.EqualError(t, err,
+		`MetricPoint value *openmetrics.MetricPoint_CounterValue of metric family "a" is missing or does not match its type gauge`)
+}
+
+func TestFromProtoInfo(t *testing.T) {
+	info := func(value string) *openmetrics.MetricPoint {
+		return &openmetrics.MetricPoint{Value: &openmetrics.MetricPoint_InfoValue{InfoValue: &openmetrics.InfoValue{
+			Info: []*openmetrics.Label{{Name: "version", Value: value}},
+		}}}
+	}
+	pms := &openmetrics.MetricSet{MetricFamilies: []*openmetrics.MetricFamily{{
+		Name: "a",
+		Type: openmetrics.MetricType_INFO,
+		Metrics: []*openmetrics.Metric{{
+			Labels:       []*openmetrics.Label{{Name: "b", Value: "c"}},
+			MetricPoints: []*openmetrics.MetricPoint{info("1"), info("2")},
+		}},
+	}}}
+	ms, err := FromProto(pms)
+	require.NoError(t, err)
+	metrics := ms.MetricFamilies[0].Metrics
+	require.Len(t, metrics, 2)
+	require.Equal(t, labels.Labels{{Name: "b", Value: "c"}, {Name: "version", Value: "1"}}, metrics[0].Labels)
+	require.Equal(t, labels.Labels{{Name: "b", Value: "c"}, {Name: "version", Value: "2"}}, metrics[1].Labels)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, ms))
+	require.Equal(t, `# TYPE a info
+a_info{b="c",version="1"} 1
+a_info{b="c",version="2"} 1
+# EOF
+`, buf.String())
+
+	converted, _, err := ToProto(ms)
+	require.NoError(t, err)
+	require.Len(t, converted.MetricFamilies[0].Metrics, 2)
+	require.Empty(t, converted.MetricFamilies[0].Metrics[0].MetricPoints[0].GetInfoValue().GetInfo())
+}
@@ -0,0 +1,42 @@
+package validator
+
+import "fmt"
+
+// Format is the format of an exposition.
+type Format int
+
+// A list of supported exposition formats.
+const (
+	// FormatText is the OpenMetrics text format.
+	FormatText Format = iota
+	// FormatProtobuf is the OpenMetrics protobuf format, a MetricSet message.
+	FormatProtobuf
+	// FormatPrometheusText is the Prometheus text format 0.0.4, only the rules
+	// which also apply to it are checked.
+	FormatPrometheusText
+)
+
+var validFormats = []Format{FormatText, FormatProtobuf, FormatPrometheusText}
+
+// String returns a readable value for the format.
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatProtobuf:
+		return "protobuf"
+	case FormatPrometheusText:
+		return "prometheus-text"
+	}
+	return ""
+}
+
+// NewFormat creates a Format from a string.
+func NewFormat(str string) (Format, error) {
+	for _, f := range validFormats {
+		if f.String() == str {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown format %q", str)
+}
@@ -0,0 +1,148 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// The lint rules check the Prometheus naming conventions, which go beyond
+// the OpenMetrics spec. They are reported at ErrorLevelLint.
+var (
+	errLintSnakeCase = errorWithLevel{
+		rule:  "lint.snake-case",
+		err:   errors.New("metric and label names should be snake_case"),
+		level: ErrorLevelLint,
+	}
+
+	errLintBaseUnit = errorWithLevel{
+		rule:  "lint.base-unit",
+		err:   errors.New("metric names should use base units, e.g. seconds rather than milliseconds"),
+		level: ErrorLevelLint,
+	}
+
+	errLintTotalSuffix = errorWithLevel{
+		rule:  "lint.total-suffix",
+		err:   errors.New("only counters should have the _total suffix"),
+		level: ErrorLevelLint,
+	}
+
+	errLintGaugeSuffix = errorWithLevel{
+		rule:  "lint.gauge-suffix",
+		err:   errors.New("gauges should not have the _count, _sum or _bucket suffixes"),
+		level: ErrorLevelLint,
+	}
+
+	errLintHelp = errorWithLevel{
+		rule:  "lint.help",
+		err:   errors.New("metric families should have a non-empty HELP"),
+		level: ErrorLevelLint,
+	}
+
+	errLintTypeInName = errorWithLevel{
+		rule:  "lint.type-in-name",
+		err:   errors.New("metric names should not contain the metric type"),
+		level: ErrorLevelLint,
+	}
+)
+
+var (
+	_snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	// _snakeCaseMetricName also allows the colons of the names of recording
+	// rules, e.g. job:requests:rate5m.
+	_snakeCaseMetricName = regexp.MustCompile(`^[a-z][a-z0-9]*([_:][a-z0-9]+)*$`)
+)
+
+// _nonBaseUnits maps the units of metric names to their base unit.
+var _nonBaseUnits = func() map[string]string {
+	res := map[string]string{
+		"minutes": "seconds",
+		"hours":   "seconds",
+		"days":    "seconds",
+		"weeks":   "seconds",
+		"bits":    "bytes",
+		"percent": "ratio",
+	}
+	prefixes := []string{"nano", "micro", "milli", "centi", "deci", "kilo", "mega", "giga", "tera", "peta",
+		"kibi", "mebi", "gibi", "tebi", "pebi"}
+	for _, base := range []string{"seconds", "bytes", "meters", "grams", "volts", "amperes", "joules"} {
+		for _, prefix := range prefixes {
+			res[prefix+base] = base
+		}
+	}
+	return res
+}()
+
+// _typeNames are the metric types which should not appear in metric names.
+var _typeNames = map[string]bool{
+	"counter":        true,
+	"gauge":          true,
+	"histogram":      true,
+	"gaugehistogram": true,
+	"summary":        true,
+	"stateset":       true,
+	"untyped":        true,
+}
+
+// lintMetricFamily checks the metric family against the Prometheus naming
+// conventions.
+func (v *OpenMetricsValidator) lintMetricFamily(mfn string, cur *metricFamily) {
+	if !_snakeCaseMetricName.MatchString(mfn) {
+		v.addMetricFamilyError(mfn, cur.pos, errLintSnakeCase.withMessage("metric name %q should be snake_case", mfn))
+	}
+	for _, name := range labelNames(cur) {
+		if !_snakeCase.MatchString(name) {
+			v.addMetricFamilyError(mfn, cur.pos, errLintSnakeCase.withMessage("label name %q should be snake_case", name))
+		}
+	}
+
+	tokens := strings.FieldsFunc(strings.ToLower(mfn), func(r rune) bool {
+		return r == '_' || r == ':'
+	})
+	for _, token := range tokens {
+		if base, ok := _nonBaseUnits[token]; ok {
+			v.addMetricFamilyError(mfn, cur.pos,
+				errLintBaseUnit.withMessage("metric name should use the base unit %q rather than %q", base, token))
+		}
+		if _typeNames[token] {
+			v.addMetricFamilyError(mfn, cur.pos,
+				errLintTypeInName.withMessage("metric name should not contain the metric type %q", token))
+		}
+	}
+
+	mt := cur.MetricType()
+	if mt != textparse.MetricTypeCounter && strings.HasSuffix(mfn, "_total") {
+		v.addMetricFamilyError(mfn, cur.pos, errLintTotalSuffix.withMessage("%s should not have the _total suffix", mt))
+	}
+	if mt == textparse.MetricTypeGauge {
+		for _, suffix := range []string{"_count", "_sum", "_bucket"} {
+			if strings.HasSuffix(mfn, suffix) {
+				v.addMetricFamilyError(mfn, cur.pos, errLintGaugeSuffix.withMessage("gauge should not have the %s suffix", suffix))
+			}
+		}
+	}
+	if cur.help == nil || *cur.help == "" {
+		v.addMetricFamilyError(mfn, cur.pos, errLintHelp)
+	}
+}
+
+// labelNames returns the sorted label names of the metric family, except for
+// the metric name.
+func labelNames(mf *metricFamily) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, m := range mf.metrics {
+		for _, l := range m.lset {
+			if l.Name != labels.MetricName && !seen[l.Name] {
+				seen[l.Name] = true
+				res = append(res, l.Name)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
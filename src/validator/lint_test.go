@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tcs := []struct {
+		name             string
+		export           string
+		expectedMessages []string
+	}{
+		{
+			name: "good",
+			export: `# TYPE http_requests counter
+# HELP http_requests Requests.
+http_requests_total{status_code="200"} 1
+# TYPE request_duration_seconds histogram
+# UNIT request_duration_seconds seconds
+# HELP request_duration_seconds Request durations.
+request_duration_seconds_bucket{le="+Inf"} 1
+request_duration_seconds_count 1
+request_duration_seconds_sum 1
+# EOF
+`,
+		},
+		{
+			name: "bad_snake_case",
+			export: `# TYPE httpRequests gauge
+# HELP httpRequests Requests.
+httpRequests{statusCode="200"} 1
+# EOF
+`,
+			expectedMessages: []string{
+				`metric name "httpRequests" should be snake_case`,
+				`label name "statusCode" should be snake_case`,
+			},
+		},
+		{
+			name: "good_recording_rule_name",
+			export: `# TYPE job:requests:rate5m gauge
+# HELP job:requests:rate5m Requests per second.
+job:requests:rate5m{job="a"} 1
+# EOF
+`,
+		},
+		{
+			name: "bad_recording_rule_name",
+			export: `# TYPE job:requestDuration_milliseconds:rate5m gauge
+# HELP job:requestDuration_milliseconds:rate5m Request durations.
+job:requestDuration_milliseconds:rate5m{jobName="a"} 1
+# EOF
+`,
+			expectedMessages: []string{
+				`metric name "job:requestDuration_milliseconds:rate5m" should be snake_case`,
+				`label name "jobName" should be snake_case`,
+				`metric name should use the base unit "seconds" rather than "milliseconds"`,
+			},
+		},
+		{
+			name: "bad_base_unit",
+			export: `# TYPE latency_milliseconds gauge
+# HELP latency_milliseconds Latency.
+latency_milliseconds 1
+# EOF
+`,
+			expectedMessages: []string{`metric name should use the base unit "seconds" rather than "milliseconds"`},
+		},
+		{
+			name: "bad_total_suffix",
+			export: `# TYPE errors_total gauge
+# HELP errors_total Errors.
+errors_total 1
+# EOF
+`,
+			expectedMessages: []string{"gauge should not have the _total suffix"},
+		},
+		{
+			name: "bad_gauge_suffix",
+			export: `# TYPE queue_count gauge
+# HELP queue_count Queue.
+queue_count 1
+# EOF
+`,
+			expectedMessages: []string{"gauge should not have the _count suffix"},
+		},
+		{
+			name: "bad_help",
+			export: `# TYPE a gauge
+a 1
+# TYPE b gauge
+# HELP b 
+b 1
+# EOF
+`,
+			expectedMessages: []string{
+				"metric families should have a non-empty HELP",
+				"metric families should have a non-empty HELP",
+			},
+		},
+		{
+			name: "bad_type_in_name",
+			export: `# TYPE requests_counter counter
+# HELP requests_counter Requests.
+requests_counter_total 1
+# EOF
+`,
+			expectedMessages: []string{`metric name should not contain the metric type "counter"`},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(ErrorLevelLint)
+			err := v.Validate([]byte(tc.export))
+			var messages []string
+			for _, vi := range v.Report().Violations {
+				require.Equal(t, ErrorLevelLint, vi.Level)
+				messages = append(messages, vi.Err.Error())
+			}
+			require.ElementsMatch(t, tc.expectedMessages, messages)
+			if len(tc.expectedMessages) == 0 {
+				require.NoError(t, err)
+			}
+
+			// The lint rules are not reported above ErrorLevelLint.
+			v = NewValidator(ErrorLevelShould)
+			require.NoError(t, v.Validate([]byte(tc.export)))
+		})
+	}
+}
+
+func TestErrorLevels(t *testing.T) {
+	// The values of the levels of the spec are kept, lint is below them.
+	require.Equal(t, ErrorLevel(0), ErrorLevelShould)
+	require.Equal(t, ErrorLevel(1), ErrorLevelMust)
+	require.Less(t, int(ErrorLevelLint), int(ErrorLevelShould))
+	for _, s := range []string{"lint", "should", "must"} {
+		el, err := NewErrorLevel(s)
+		require.NoError(t, err)
+		require.Equal(t, s, el.String())
+	}
+	_, err := NewErrorLevel("may")
+	require.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+)
+
+// MultiTargetValidator validates the expositions of many targets, e.g. the
+// endpoints of a scrape pool. Each target has its own OpenMetricsValidator,
+// so the history expositions are compared with is kept per target.
+//
+// It is safe for concurrent use: the expositions of different targets are
+// validated in parallel, and the ones of the same target one at a time.
+type MultiTargetValidator struct {
+	level ErrorLevel
+	opts  []Option
+
+	mtx     sync.Mutex
+	targets map[string]*targetValidator
+}
+
+type targetValidator struct {
+	mtx sync.Mutex
+	v   *OpenMetricsValidator
+}
+
+// NewMultiTargetValidator creates a MultiTargetValidator, the validator of each
+// target is created with the level and options. Options shared by the targets,
+// e.g. the clock of WithClock, must be safe for concurrent use.
+func NewMultiTargetValidator(level ErrorLevel, opts ...Option) *MultiTargetValidator {
+	return &MultiTargetValidator{
+		level:   level,
+		opts:    opts,
+		targets: make(map[string]*targetValidator),
+	}
+}
+
+// target returns the validator of the target, and creates it if needed.
+func (m *MultiTargetValidator) target(target string) *targetValidator {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	tv, ok := m.targets[target]
+	if !ok {
+		tv = &targetValidator{v: NewValidator(m.level, m.opts...)}
+		m.targets[target] = tv
+	}
+	return tv
+}
+
+// Validate validates the exposition of the target, see
+// OpenMetricsValidator.Validate. The returned error only holds the violations
+// of this exposition.
+func (m *MultiTargetValidator) Validate(target string, b []byte) error {
+	tv := m.target(target)
+	tv.mtx.Lock()
+	defer tv.mtx.Unlock()
+	return tv.v.Validate(b)
+}
+
+// ValidateReader reads and validates the exposition of the target, see
+// OpenMetricsValidator.ValidateReader.
+func (m *MultiTargetValidator) ValidateReader(ctx context.Context, target string, r io.Reader) error {
+	tv := m.target(target)
+	tv.mtx.Lock()
+	defer tv.mtx.Unlock()
+	return tv.v.ValidateReader(ctx, r)
+}
+
+// Report returns the violations found in the last exposition of the target.
+func (m *MultiTargetValidator) Report(target string) Report {
+	m.mtx.Lock()
+	tv, ok := m.targets[target]
+	m.mtx.Unlock()
+	if !ok {
+		return Report{}
+	}
+	tv.mtx.Lock()
+	defer tv.mtx.Unlock()
+	return tv.v.Report()
+}
+
+// Reports returns the reports of the last exposition of all the targets, keyed
+// by target.
+func (m *MultiTargetValidator) Reports() map[string]Report {
+	res := make(map[string]Report)
+	for _, target := range m.Targets() {
+		res[target] = m.Report(target)
+	}
+	return res
+}
+
+// Targets returns the sorted targets which have been validated.
+func (m *MultiTargetValidator) Targets() []string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	res := make([]string, 0, len(m.targets))
+	for target := range m.targets {
+		res = append(res, target)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Reset resets the history and the report of the target.
+func (m *MultiTargetValidator) Reset(target string) {
+	m.mtx.Lock()
+	tv, ok := m.targets[target]
+	m.mtx.Unlock()
+	if !ok {
+		return
+	}
+	tv.mtx.Lock()
+	defer tv.mtx.Unlock()
+	tv.v.Reset()
+}
+
+// Remove forgets the target, e.g. once it is no longer scraped.
+func (m *MultiTargetValidator) Remove(target string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.targets, target)
+}
@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiTargetValidator(t *testing.T) {
+	m := NewMultiTargetValidator(ErrorLevelMust)
+	export := func(value int) []byte {
+		return []byte(fmt.Sprintf("# TYPE a counter\na_total %d\n# EOF\n", value))
+	}
+
+	var (
+		wg     sync.WaitGroup
+		failed = make([]int, 8)
+	)
+	for i := 0; i < 8; i++ {
+		i := i
+		target := fmt.Sprintf("target-%d", i)
+		decreasing := i%2 == 1
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				value := j
+				if decreasing {
+					value = 10 - j
+				}
+				if m.Validate(target, export(value)) != nil {
+					failed[i]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, m.Targets(), 8)
+	reports := m.Reports()
+	for i := 0; i < 8; i++ {
+		report := reports[fmt.Sprintf("target-%d", i)]
+		if i%2 == 0 {
+			require.Zero(t, failed[i])
+			require.Empty(t, report.Violations)
+			continue
+		}
+		// The history is isolated per target, so only the decreasing targets
+		// report violations, and the report only holds the last exposition.
+		require.Equal(t, 9, failed[i])
+		require.Len(t, report.Violations, 1)
+		require.Equal(t, RuleID("counter.monotonic"), report.Violations[0].Rule)
+	}
+
+	m.Reset("target-1")
+	require.Empty(t, m.Report("target-1").Violations)
+	m.Remove("target-1")
+	require.Len(t, m.Targets(), 7)
+	require.Empty(t, m.Report("unknown").Violations)
+}
+
+func TestMultiTargetValidatorParseError(t *testing.T) {
+	m := NewMultiTargetValidator(ErrorLevelMust)
+	export := []byte("# TYPE a counter\na_total 1\n# EOF\n")
+	require.NoError(t, m.Validate("target", export))
+	require.Error(t, m.Validate("target", []byte("# TYPE a counter\na_total x\n# EOF\n")))
+	require.Len(t, m.Report("target").Violations, 1)
+
+	// The malformed exposition neither leaks into the following ones nor is
+	// reported again.
+	for i := 0; i < 2; i++ {
+		require.NoError(t, m.Validate("target", export))
+		require.Empty(t, m.Report("target").Violations)
+	}
+}
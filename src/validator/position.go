@@ -0,0 +1,237 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Position is the location of an entry within an exposition.
+type Position struct {
+	// Offset is the byte offset, starting at 0.
+	Offset int
+	// Line is the line number, starting at 1.
+	Line int
+	// Column is the column number in bytes, starting at 1.
+	Column int
+}
+
+// IsValid reports whether the position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position as "line:column", or "-" if the position is
+// not known.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// at returns the position offset bytes further on the same line.
+func (p Position) at(offset int) Position {
+	p.Offset += offset
+	p.Column += offset
+	return p
+}
+
+// advance returns the position following b, which starts at the position
+// and is made of whole lines.
+func (p Position) advance(b []byte) Position {
+	p.Offset += len(b)
+	p.Line += bytes.Count(b, []byte("\n"))
+	return p
+}
+
+// lineTracker tracks the position of the entries returned by the OpenMetrics
+// parser. Every OpenMetrics entry, including "# EOF", occupies exactly one line
+// so the position of an entry is the start of the next unread line.
+type lineTracker struct {
+	b []byte
+	// offset is the offset of the next unread line within b.
+	offset int
+	pos    Position
+	// entry is the line returned by the last call to next.
+	entry []byte
+	// skipBlankLines is set for the Prometheus text parser, which allows
+	// blank lines between the entries.
+	skipBlankLines bool
+}
+
+// newLineTracker creates a lineTracker for b, whose first line is at base.
+func newLineTracker(b []byte, base Position) *lineTracker {
+	return &lineTracker{
+		b:   b,
+		pos: base,
+	}
+}
+
+// next returns the position of the next line and advances past it.
+func (t *lineTracker) next() Position {
+	for t.skipBlankLines && t.offset < len(t.b) && isBlankLine(t.line()) {
+		t.advance()
+	}
+	pos := t.pos
+	t.entry = t.line()
+	t.advance()
+	return pos
+}
+
+// line returns the next unread line, including its line feed.
+func (t *lineTracker) line() []byte {
+	if i := bytes.IndexByte(t.b[t.offset:], '\n'); i >= 0 {
+		return t.b[t.offset : t.offset+i+1]
+	}
+	return t.b[t.offset:]
+}
+
+func (t *lineTracker) advance() {
+	n := len(t.line())
+	t.offset += n
+	t.pos.Offset += n
+	t.pos.Line++
+}
+
+// isBlankLine returns whether the line only contains spaces and tabs.
+func isBlankLine(line []byte) bool {
+	return len(bytes.Trim(line, " \t\n")) == 0
+}
+
+// sampleColumns are the byte offsets of the fields of a sample within its
+// line, an offset is 0 if the sample does not have the field.
+type sampleColumns struct {
+	value, timestamp, exemplar int
+}
+
+// newSampleColumns returns the offsets of the fields of the sample line.
+func newSampleColumns(line []byte) sampleColumns {
+	var (
+		res    sampleColumns
+		fields = splitFields(line)
+	)
+	if len(fields) > 1 {
+		res.value = fields[1]
+	}
+	for i, off := range fields {
+		if i < 2 {
+			continue
+		}
+		if line[off] == '#' {
+			res.exemplar = off
+			break
+		}
+		res.timestamp = off
+	}
+	return res
+}
+
+// splitFields returns the offsets of the fields of a sample line, which are
+// separated by spaces and tabs outside of the label sets. The labels of the
+// metric are part of its first field.
+func splitFields(line []byte) []int {
+	var (
+		fields  []int
+		inField bool
+		braces  bool
+		quoted  bool
+	)
+	for i := 0; i < len(line) && line[i] != '\n'; i++ {
+		c := line[i]
+		switch {
+		case quoted:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quoted = false
+			}
+			continue
+		case braces:
+			if c == '"' {
+				quoted = true
+			} else if c == '}' {
+				braces = false
+			}
+			continue
+		case c == ' ' || c == '\t':
+			inField = false
+			continue
+		case c == '{':
+			braces = true
+			// The labels of the metric may be separated from its name.
+			if len(fields) == 1 && !inField && line[fields[0]] != '#' {
+				inField = true
+				continue
+			}
+		}
+		if !inField {
+			fields = append(fields, i)
+			inField = true
+		}
+	}
+	return fields
+}
+
+// parseErrorFields are the fields of a sample line the parse errors point to,
+// the field of an exemplar error is relative to the "#" which starts it.
+var parseErrorFields = []struct {
+	message  string
+	exemplar bool
+	field    int
+}{
+	{message: "expected value after metric", field: 1},
+	{message: "expected timestamp or # symbol", field: 2},
+	{message: "expected timestamp or new record", field: 2},
+	{message: "invalid timestamp", field: 2},
+	{message: "expected next entry after timestamp", field: 3},
+	{message: "does not support exemplars", exemplar: true},
+	{message: "expected value after exemplar labels", exemplar: true, field: 2},
+	{message: "expected timestamp or comment", exemplar: true, field: 3},
+	{message: "invalid exemplar timestamp", exemplar: true, field: 3},
+	{message: "expected next entry after exemplar timestamp", exemplar: true, field: 4},
+}
+
+// parseErrorColumn returns the offset of the field of the line the parse error
+// points to, e.g. the value which is not a number, or the end of the line if
+// the field is missing. It returns 0 if the error does not point to a field.
+func parseErrorColumn(line []byte, err error) int {
+	fields := splitFields(line)
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		for i, off := range fields {
+			end := bytes.IndexAny(line[off:], " \t\n")
+			if end < 0 {
+				end = len(line) - off
+			}
+			if i > 0 && string(line[off:off+end]) == numErr.Num {
+				return off
+			}
+		}
+		return 0
+	}
+	for _, pf := range parseErrorFields {
+		if !strings.Contains(err.Error(), pf.message) {
+			continue
+		}
+		field := pf.field
+		if pf.exemplar {
+			cols := newSampleColumns(line)
+			if cols.exemplar == 0 {
+				return 0
+			}
+			for i, off := range fields {
+				if off == cols.exemplar {
+					field += i
+				}
+			}
+		}
+		if field < len(fields) {
+			return fields[field]
+		}
+		return len(bytes.TrimRight(line, "\n"))
+	}
+	return 0
+}
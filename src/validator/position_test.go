@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSampleColumns(t *testing.T) {
+	tcs := []struct {
+		line     string
+		expected sampleColumns
+	}{
+		{line: "a 1\n", expected: sampleColumns{value: 2}},
+		{line: "a{b=\"c d\"} 1 2\n", expected: sampleColumns{value: 11, timestamp: 13}},
+		{line: "a_total 1 # {b=\"} #\"} 2 3\n", expected: sampleColumns{value: 8, exemplar: 10}},
+		{line: "a_total 1 2 # {} 3\n", expected: sampleColumns{value: 8, timestamp: 10, exemplar: 12}},
+		{line: "  a {b=\"\\\"\"}\t1\n", expected: sampleColumns{value: 13}},
+		{line: "a\n", expected: sampleColumns{}},
+	}
+	for _, tc := range tcs {
+		require.Equal(t, tc.expected, newSampleColumns([]byte(tc.line)), tc.line)
+	}
+}
+
+func TestParseErrorColumn(t *testing.T) {
+	tcs := []struct {
+		name     string
+		format   Format
+		line     string
+		expected Position
+	}{
+		{
+			name:     "invalid_value",
+			line:     "a{b=\"x\"} x",
+			expected: Position{Offset: 9, Line: 1, Column: 10},
+		},
+		{
+			name:     "missing_value",
+			line:     "a{b=\"x\"}",
+			expected: Position{Offset: 8, Line: 1, Column: 9},
+		},
+		{
+			name:     "invalid_timestamp",
+			line:     "a 1 x",
+			expected: Position{Offset: 4, Line: 1, Column: 5},
+		},
+		{
+			name:     "extra_field",
+			line:     "a 1 1 1",
+			expected: Position{Offset: 6, Line: 1, Column: 7},
+		},
+		{
+			name:     "exemplar_not_supported",
+			line:     "a 1 # {} 1",
+			expected: Position{Offset: 4, Line: 1, Column: 5},
+		},
+		{
+			name:     "invalid_exemplar_value",
+			line:     "a_total 1 # {} x",
+			expected: Position{Offset: 15, Line: 1, Column: 16},
+		},
+		{
+			name:     "invalid_exemplar_timestamp",
+			line:     "a_total 1 # {} 1 x",
+			expected: Position{Offset: 17, Line: 1, Column: 18},
+		},
+		{
+			name:     "invalid_label",
+			line:     "a{b} 1",
+			expected: Position{Offset: 0, Line: 1, Column: 1},
+		},
+		{
+			name:     "prometheus_invalid_value",
+			format:   FormatPrometheusText,
+			line:     "  a x",
+			expected: Position{Offset: 4, Line: 1, Column: 5},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(ErrorLevelMust, WithFormat(tc.format))
+			require.Error(t, v.Validate([]byte(tc.line+"\n# EOF\n")))
+			violations := v.Report().Violations
+			require.Len(t, violations, 1)
+			require.Equal(t, RuleID("parse"), violations[0].Rule)
+			require.Equal(t, tc.expected, violations[0].Pos)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+)
+
+var errShouldValueBeExactlyRepresentable = errorWithLevel{
+	rule:  "value.precision-loss",
+	err:   errors.New("integer values SHOULD be exactly representable as a float64"),
+	level: ErrorLevelShould,
+}
+
+// sampleValue returns the lexical value of the sample on the line, which
+// follows the series.
+func sampleValue(line, series []byte) []byte {
+	i := bytes.Index(line, series)
+	if i < 0 || len(series) == 0 {
+		return nil
+	}
+	rest := bytes.TrimLeft(line[i+len(series):], " \t")
+	if j := bytes.IndexAny(rest, " \t\n"); j >= 0 {
+		rest = rest[:j]
+	}
+	return rest
+}
+
+// parseInexactInt returns the integer of the lexical value if it cannot be
+// represented exactly as a float64, e.g. a uint64 counter above 2^53, and
+// nil otherwise.
+func parseInexactInt(b []byte) *big.Int {
+	digits := b
+	if len(digits) > 0 && (digits[0] == '+' || digits[0] == '-') {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 {
+		return nil
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil
+		}
+	}
+	n, ok := new(big.Int).SetString(string(b), 10)
+	if !ok {
+		return nil
+	}
+	return inexact(n)
+}
+
+// inexactInt returns the integer if it cannot be represented exactly as a
+// float64, and nil otherwise.
+func inexactInt(i int64) *big.Int {
+	return inexact(big.NewInt(i))
+}
+
+// inexactUint is inexactInt for unsigned integers.
+func inexactUint(u uint64) *big.Int {
+	return inexact(new(big.Int).SetUint64(u))
+}
+
+func inexact(n *big.Int) *big.Int {
+	if _, acc := new(big.Float).SetInt(n).Float64(); acc == big.Exact {
+		return nil
+	}
+	return n
+}
+
+// validateValuePrecision warns when the value of the sample is an integer
+// which is rounded by float64, two distinct values may then compare equal.
+func (v *OpenMetricsValidator) validateValuePrecision(cur metric) {
+	if cur.exactValue == nil {
+		return
+	}
+	v.addMetricErrorAt(cur, cur.columns.value, errShouldValueBeExactlyRepresentable.withMessage(
+		"integer value %s cannot be represented exactly as a float64, it is rounded to %v", cur.exactValue, cur.value))
+}
+
+// exactInt returns the value of the metric as an exact integer, it returns
+// false if the value is not an integer.
+func exactInt(m metric) (*big.Int, bool) {
+	if m.exactValue != nil {
+		return m.exactValue, true
+	}
+	if math.IsInf(m.value, 0) || math.IsNaN(m.value) || m.value != math.Trunc(m.value) {
+		return nil, false
+	}
+	n, _ := big.NewFloat(m.value).Int(nil)
+	return n, true
+}
+
+// compareValues compares the values of the metrics, exactly if one of them is
+// an integer which float64 cannot represent and the other one is an integer
+// too. It returns -1, 0 or +1 like big.Int.Cmp, NaNs compare equal to anything.
+func compareValues(a, b metric) int {
+	if a.exactValue != nil || b.exactValue != nil {
+		x, okX := exactInt(a)
+		y, okY := exactInt(b)
+		if okX && okY {
+			return x.Cmp(y)
+		}
+	}
+	switch {
+	case a.value < b.value:
+		return -1
+	case a.value > b.value:
+		return 1
+	}
+	return 0
+}
+
+// displayValues returns the values of the metrics for messages, as exact
+// integers if float64 rounds one of them.
+func displayValues(a, b metric) (interface{}, interface{}) {
+	if a.exactValue != nil || b.exactValue != nil {
+		x, okX := exactInt(a)
+		y, okY := exactInt(b)
+		if okX && okY {
+			return x, y
+		}
+	}
+	return a.value, b.value
+}
@@ -0,0 +1,128 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateValuePrecision(t *testing.T) {
+	counter := func(value string) string {
+		return fmt.Sprintf("# TYPE a counter\n# HELP a help\na_total %s\n# EOF\n", value)
+	}
+	tcs := []struct {
+		name          string
+		format        Format
+		exports       []string
+		expectedRules []RuleID
+	}{
+		{
+			name:    "good_exact_counter",
+			exports: []string{counter("9007199254740992"), counter("9007199254740993.5e0")},
+		},
+		{
+			name:          "bad_inexact_counter",
+			exports:       []string{counter("9007199254740993")},
+			expectedRules: []RuleID{"value.precision-loss"},
+		},
+		{
+			name:          "bad_inexact_counter_increase",
+			exports:       []string{counter("9007199254740992"), counter("9007199254740993")},
+			expectedRules: []RuleID{"value.precision-loss"},
+		},
+		{
+			// Both values are rounded to 2^53, only the exact comparison
+			// detects the decrease.
+			name:          "bad_inexact_counter_decrease",
+			exports:       []string{counter("9007199254740993"), counter("9007199254740992")},
+			expectedRules: []RuleID{"value.precision-loss", "counter.monotonic"},
+		},
+		{
+			name:          "bad_uint64_counter_decrease",
+			exports:       []string{counter("18446744073709551615"), counter("18446744073709551614")},
+			expectedRules: []RuleID{"value.precision-loss", "value.precision-loss", "counter.monotonic"},
+		},
+		{
+			name:          "bad_prometheus_inexact_gauge",
+			format:        FormatPrometheusText,
+			exports:       []string{"# TYPE a gauge\n# HELP a help\n  a{b=\"1\"}   -9223372036854775807 1000\n"},
+			expectedRules: []RuleID{"value.precision-loss"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(ErrorLevelShould, WithClock(testNowFn()), WithFormat(tc.format))
+			var ruleIDs []RuleID
+			for _, export := range tc.exports {
+				_ = v.Validate([]byte(export))
+				for _, vi := range v.Report().Violations {
+					ruleIDs = append(ruleIDs, vi.Rule)
+				}
+			}
+			require.Equal(t, tc.expectedRules, ruleIDs)
+		})
+	}
+}
+
+func TestValidateValuePrecisionMessage(t *testing.T) {
+	v := testValidator(ErrorLevelMust)
+	require.NoError(t, v.Validate([]byte("# TYPE a counter\na_total 9007199254740993\n# EOF\n")))
+	err := v.Validate([]byte("# TYPE a counter\na_total 9007199254740992\n# EOF\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "last=9007199254740993, cur=9007199254740992")
+}
+
+func TestSaveAndLoadStatePrecision(t *testing.T) {
+	v := testValidator(ErrorLevelMust)
+	require.NoError(t, v.Validate([]byte("# TYPE a counter\na_total 9007199254740993\n# EOF\n")))
+	var buf bytes.Buffer
+	require.NoError(t, v.SaveState(&buf))
+	require.Contains(t, buf.String(), `"value":"9007199254740993"`)
+
+	restored := testValidator(ErrorLevelMust)
+	require.NoError(t, restored.LoadState(bytes.NewReader(buf.Bytes())))
+	err := restored.Validate([]byte("# TYPE a counter\na_total 9007199254740992\n# EOF\n"))
+	require.Error(t, err)
+}
+
+func TestSampleValue(t *testing.T) {
+	tcs := []struct {
+		line, series, expected string
+	}{
+		{line: "a 1\n", series: "a", expected: "1"},
+		{line: "a{b=\"1 2\"} 3 4 # {} 5\n", series: "a{b=\"1 2\"}", expected: "3"},
+		{line: "\ta\t-7", series: "a", expected: "-7"},
+		{line: "a 1\n", series: "b", expected: ""},
+	}
+	for _, tc := range tcs {
+		require.Equal(t, tc.expected, string(sampleValue([]byte(tc.line), []byte(tc.series))), tc.line)
+	}
+}
+
+func TestParseInexactInt(t *testing.T) {
+	require.Nil(t, parseInexactInt([]byte("9007199254740992")))
+	require.Nil(t, parseInexactInt([]byte("9223372036854775808")))
+	require.Nil(t, parseInexactInt([]byte("9007199254740993.0")))
+	require.Nil(t, parseInexactInt([]byte("1e20")))
+	require.Nil(t, parseInexactInt([]byte("-")))
+	require.Equal(t, "9007199254740993", parseInexactInt([]byte("9007199254740993")).String())
+	require.Equal(t, "-9007199254740993", parseInexactInt([]byte("-9007199254740993")).String())
+	require.Equal(t, "18446744073709551615", inexactUint(1<<64-1).String())
+	require.Nil(t, inexactInt(1<<62))
+}
+
+func TestCompareValues(t *testing.T) {
+	exact := func(s string) metric {
+		n, _ := new(big.Int).SetString(s, 10)
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return metric{value: f, exactValue: inexact(n)}
+	}
+	require.Equal(t, 1, compareValues(exact("9007199254740993"), exact("9007199254740992")))
+	require.Equal(t, -1, compareValues(exact("9007199254740992"), exact("9007199254740993")))
+	require.Equal(t, 0, compareValues(exact("9007199254740993"), exact("9007199254740993")))
+	require.Equal(t, 1, compareValues(exact("9007199254740993"), metric{value: 1.5}))
+	require.Equal(t, -1, compareValues(metric{value: 1}, metric{value: 2}))
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// _openMetricsOnlyRules are the rules the Prometheus text format does not
+// have. Their violations are reported as warnings about what would break
+// when switching the exposition to OpenMetrics.
+var _openMetricsOnlyRules = map[RuleID]bool{
+	errMustSampleSuffixBeValid.rule:       true,
+	errMustNotMetricFamilyNamesClash.rule: true,
+	errMustHistogramValueBeInteger.rule:   true,
+}
+
+// pendingHelp is a HELP entry of the Prometheus text format whose metric
+// family is not known yet, since the TYPE which usually follows it may
+// rename it.
+type pendingHelp struct {
+	name string
+	help string
+	pos  Position
+}
+
+// openMetricsWarning returns the error as a warning if its rule only applies
+// to OpenMetrics.
+func openMetricsWarning(err error) error {
+	var ewl errorWithLevel
+	if !errors.As(err, &ewl) || !_openMetricsOnlyRules[ewl.rule] {
+		return err
+	}
+	ewl = ewl.withMessage("would not be valid OpenMetrics: %v", ewl.err)
+	ewl.level = ErrorLevelShould
+	return ewl
+}
+
+// prometheusMetricFamilyName returns the name of the metric family of a TYPE
+// entry in the Prometheus text format. Counters are named after their
+// samples there, so the _total suffix is removed to match OpenMetrics.
+func prometheusMetricFamilyName(name string, mt textparse.MetricType) string {
+	if mt == textparse.MetricTypeCounter && strings.HasSuffix(name, "_total") {
+		return strings.TrimSuffix(name, "_total")
+	}
+	return name
+}
+
+// recordPrometheusType records a TYPE entry of the Prometheus text format and
+// the HELP entry preceding it.
+func (v *OpenMetricsValidator) recordPrometheusType(name string, mt textparse.MetricType) {
+	mfn := prometheusMetricFamilyName(name, mt)
+	help := v.pendingHelp
+	if help != nil && help.name != name {
+		v.flushPrometheusHelp()
+		help = nil
+	}
+	v.pendingHelp = nil
+	v.recordMetricType(mfn, mt, &v.dataPointFound, &v.metadata)
+	if help != nil {
+		pos := v.pos
+		v.pos = help.pos
+		v.recordHelp(mfn, help.help, &v.dataPointFound, &v.metadata)
+		v.pos = pos
+	}
+}
+
+// recordPrometheusHelp records a HELP entry of the Prometheus text format. It
+// is recorded once the metric family is known, which is when the next entry
+// is read unless the HELP follows the TYPE of a counter.
+func (v *OpenMetricsValidator) recordPrometheusHelp(name, help string) {
+	v.flushPrometheusHelp()
+	if !v.dataPointFound && v.metadata.Type == textparse.MetricTypeCounter &&
+		v.metadata.Metric+"_total" == name {
+		v.recordHelp(v.metadata.Metric, help, &v.dataPointFound, &v.metadata)
+		return
+	}
+	v.pendingHelp = &pendingHelp{name: name, help: help, pos: v.pos}
+}
+
+// flushPrometheusHelp records the pending HELP entry under its own name.
+func (v *OpenMetricsValidator) flushPrometheusHelp() {
+	help := v.pendingHelp
+	if help == nil {
+		return
+	}
+	v.pendingHelp = nil
+	pos := v.pos
+	v.pos = help.pos
+	v.recordHelp(help.name, help.help, &v.dataPointFound, &v.metadata)
+	v.pos = pos
+}
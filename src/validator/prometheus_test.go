@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePrometheusText(t *testing.T) {
+	tcs := []struct {
+		name          string
+		level         ErrorLevel
+		exports       []string
+		expectedRules []RuleID
+	}{
+		{
+			name:  "good_exposition",
+			level: ErrorLevelShould,
+			exports: []string{`# HELP a_total help
+# TYPE a_total counter
+a_total{a="1"} 1
+
+# HELP b help
+# TYPE b histogram
+b_bucket{le="1"} 0
+b_bucket{le="+Inf"} 1
+b_count 1
+b_sum 2
+# HELP c help
+c{a="1"} 3
+`},
+		},
+		{
+			name:  "bad_counter_decreases",
+			level: ErrorLevelMust,
+			exports: []string{`# TYPE a_total counter
+a_total 2
+`, `# TYPE a_total counter
+a_total 1
+`},
+			expectedRules: []RuleID{"counter.monotonic"},
+		},
+		{
+			name:  "bad_histogram_buckets",
+			level: ErrorLevelMust,
+			exports: []string{`# TYPE a histogram
+a_bucket{le="1"} 2
+a_bucket{le="2"} 1
+a_count 1
+a_sum 1
+`},
+			expectedRules: []RuleID{"histogram.buckets-cumulative", "histogram.inf-bucket"},
+		},
+		{
+			name:  "bad_metric_families_interleave",
+			level: ErrorLevelMust,
+			exports: []string{`a 1
+b 1
+a{c="d"} 1
+`},
+			expectedRules: []RuleID{"family.interleaved"},
+		},
+		{
+			name:  "bad_timestamp_mixed",
+			level: ErrorLevelMust,
+			exports: []string{`a{b="1"} 1 1000
+a{b="2"} 1
+`},
+			expectedRules: []RuleID{"timestamp.mixed-presence"},
+		},
+		{
+			name:  "warning_counter_without_total",
+			level: ErrorLevelShould,
+			exports: []string{`# TYPE a counter
+a 1
+`},
+			expectedRules: []RuleID{"family.sample-suffix"},
+		},
+		{
+			name:  "warning_histogram_value_not_integer",
+			level: ErrorLevelShould,
+			exports: []string{`# TYPE a histogram
+a_bucket{le="+Inf"} 1.5
+a_count 1.5
+a_sum 1
+`},
+			expectedRules: []RuleID{"histogram.integer-values", "histogram.integer-values"},
+		},
+		{
+			name:  "no_warning_at_must_level",
+			level: ErrorLevelMust,
+			exports: []string{`# TYPE a counter
+a 1
+# TYPE a_created gauge
+a_created 1
+`},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := testValidator(tc.level)
+			WithFormat(FormatPrometheusText)(v)
+			var err error
+			for _, export := range tc.exports {
+				err = v.Validate([]byte(export))
+			}
+			var ruleIDs []RuleID
+			for _, vi := range v.Report().Violations {
+				ruleIDs = append(ruleIDs, vi.Rule)
+			}
+			require.ElementsMatch(t, tc.expectedRules, ruleIDs)
+			if len(tc.expectedRules) == 0 {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePrometheusTextWarning(t *testing.T) {
+	v := NewValidator(ErrorLevelShould, WithFormat(FormatPrometheusText))
+	require.Error(t, v.Validate([]byte("# TYPE a counter\n\n  \na 1\n")))
+
+	report := v.Report()
+	require.Len(t, report.Violations, 1)
+	vi := report.Violations[0]
+	require.Equal(t, ErrorLevelShould, vi.Level)
+	require.EqualError(t, vi.Err,
+		`would not be valid OpenMetrics: sample name "a" is not valid for metric family "a" of type counter`)
+	require.Equal(t, Position{Offset: 21, Line: 4, Column: 1}, vi.Pos)
+}
+
+func TestValidatePrometheusTextReader(t *testing.T) {
+	exports := []string{`# HELP a_total help
+# TYPE a_total counter
+a_total{a="1"} 3
+# TYPE b gauge
+b{a="1"} 1
+b{a="2"} 1
+a_total{a="2"} 1
+`, `# HELP a_total help
+# TYPE a_total counter
+a_total{a="1"} 2
+
+# TYPE b gauge
+b{a="1"} 1
+`}
+
+	expected := testValidator(ErrorLevelShould)
+	WithFormat(FormatPrometheusText)(expected)
+	actual := testValidator(ErrorLevelShould)
+	WithFormat(FormatPrometheusText)(actual)
+	for _, export := range exports {
+		expectedErr := expected.Validate([]byte(export))
+		actualErr := actual.ValidateReader(context.Background(), strings.NewReader(export))
+		require.Equal(t, expectedErr == nil, actualErr == nil)
+	}
+	require.NotEmpty(t, actual.Report().Violations)
+	require.ElementsMatch(t, expected.Report().Violations, actual.Report().Violations)
+}
@@ -0,0 +1,298 @@
+package validator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/scrape"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/OpenObservability/OpenMetrics/src/proto/openmetrics"
+)
+
+var _protoMetricTypes = map[openmetrics.MetricType]textparse.MetricType{
+	openmetrics.MetricType_UNKNOWN:         textparse.MetricTypeUnknown,
+	openmetrics.MetricType_GAUGE:           textparse.MetricTypeGauge,
+	openmetrics.MetricType_COUNTER:         textparse.MetricTypeCounter,
+	openmetrics.MetricType_STATE_SET:       textparse.MetricTypeStateset,
+	openmetrics.MetricType_INFO:            textparse.MetricTypeInfo,
+	openmetrics.MetricType_HISTOGRAM:       textparse.MetricTypeHistogram,
+	openmetrics.MetricType_GAUGE_HISTOGRAM: textparse.MetricTypeGaugeHistogram,
+	openmetrics.MetricType_SUMMARY:         textparse.MetricTypeSummary,
+}
+
+// DecodeMetricSet decodes a protobuf encoded MetricSet.
+func DecodeMetricSet(b []byte) (*openmetrics.MetricSet, error) {
+	var ms openmetrics.MetricSet
+	if err := proto.Unmarshal(b, &ms); err != nil {
+		return nil, err
+	}
+	return &ms, nil
+}
+
+// ValidateProto decodes a protobuf encoded MetricSet and validates the metrics
+// against OpenMetrics spec.
+func (v *OpenMetricsValidator) ValidateProto(b []byte) error {
+	ms, err := DecodeMetricSet(b)
+	if err != nil {
+		v.startScrape()
+		v.addViolation(Violation{}, errParse.withMessage("%v", err))
+		return v.report.Err()
+	}
+	return v.ValidateMetricSet(ms)
+}
+
+// ValidateMetricSet validates the metrics of a MetricSet against OpenMetrics
+// spec. The MetricPoints are validated as the samples they are exposed as in
+// the text format, so the same rules apply to both formats. Violations have no
+// position since the protobuf format has no lines.
+func (v *OpenMetricsValidator) ValidateMetricSet(ms *openmetrics.MetricSet) error {
+	v.startScrape()
+	v.pos = Position{}
+	for _, mf := range ms.GetMetricFamilies() {
+		v.recordProtoMetricFamily(mf)
+	}
+	v.validateRecorded()
+	return v.report.Err()
+}
+
+func (v *OpenMetricsValidator) recordProtoMetricFamily(mf *openmetrics.MetricFamily) {
+	mfn := mf.GetName()
+	if !model.IsValidMetricName(model.LabelValue(mfn)) {
+		v.addMetricFamilyError(mfn, v.pos, errMustProtoNameBeValid.withMessage("invalid metric family name %q", mfn))
+		return
+	}
+	mt, ok := _protoMetricTypes[mf.GetType()]
+	if !ok {
+		v.addMetricFamilyError(mfn, v.pos, errMustProtoValueMatchType.withMessage("unknown metric type %v", mf.GetType()))
+		return
+	}
+
+	// Every metric family starts new metadata.
+	v.metadata, v.dataPointFound = scrape.MetricMetadata{}, false
+	v.recordMetricType(mfn, mt, &v.dataPointFound, &v.metadata)
+	if mf.GetHelp() != "" {
+		v.recordHelp(mfn, mf.GetHelp(), &v.dataPointFound, &v.metadata)
+	}
+	if mf.GetUnit() != "" {
+		v.recordUnit(mfn, mf.GetUnit(), &v.dataPointFound, &v.metadata)
+		// The text parser already rejects a unit which is not a suffix of the
+		// metric family name, only the protobuf format needs the rule.
+		if !strings.HasSuffix(mfn, "_"+mf.GetUnit()) {
+			v.addMetricFamilyError(mfn, v.pos, errMustUnitBeNameSuffix)
+		}
+	}
+
+	for _, m := range mf.GetMetrics() {
+		lset, ok := v.protoLabels(mfn, m.GetLabels())
+		if !ok {
+			continue
+		}
+		for _, mp := range m.GetMetricPoints() {
+			v.recordProtoMetricPoint(mfn, mf.GetType(), lset, mp)
+			v.dataPointFound = true
+		}
+	}
+}
+
+// protoLabels converts the labels, it returns false if a label name is invalid.
+func (v *OpenMetricsValidator) protoLabels(mfn string, ls []*openmetrics.Label) (labels.Labels, bool) {
+	lset := make(labels.Labels, 0, len(ls))
+	for _, l := range ls {
+		if !model.LabelName(l.GetName()).IsValid() {
+			v.addMetricFamilyError(mfn, v.pos, errMustProtoNameBeValid.withMessage("invalid label name %q", l.GetName()))
+			return nil, false
+		}
+		lset = append(lset, labels.Label{Name: l.GetName(), Value: l.GetValue()})
+	}
+	return lset, true
+}
+
+// recordProtoMetricPoint records the samples the MetricPoint is exposed as in
+// the text format.
+func (v *OpenMetricsValidator) recordProtoMetricPoint(
+	mfn string,
+	mt openmetrics.MetricType,
+	lset labels.Labels,
+	mp *openmetrics.MetricPoint,
+) {
+	t, withTimestamp := v.scrapeTime, mp.GetTimestamp() != nil
+	if withTimestamp {
+		t = protoTimestamp(mp.GetTimestamp())
+	}
+	recordExact := func(suffix string, extra labels.Labels, value float64, exactValue *big.Int, e *openmetrics.Exemplar) {
+		sampleLset := append(append(labels.Labels{{Name: labels.MetricName, Value: mfn + suffix}}, lset...), extra...)
+		v.recordMetric(mfn+suffix, labels.New(sampleLset...), t, value, exactValue, protoExemplar(e), withTimestamp)
+	}
+	record := func(suffix string, extra labels.Labels, value float64, e *openmetrics.Exemplar) {
+		recordExact(suffix, extra, value, nil, e)
+	}
+	recordCount := func(suffix string, extra labels.Labels, count uint64, e *openmetrics.Exemplar) {
+		recordExact(suffix, extra, float64(count), inexactUint(count), e)
+	}
+	recordCreated := func(created *timestamppb.Timestamp) {
+		if created != nil {
+			record("_created", nil, float64(protoTimestamp(created))/1000, nil)
+		}
+	}
+
+	switch value := mp.GetValue().(type) {
+	case *openmetrics.MetricPoint_UnknownValue:
+		if mt != openmetrics.MetricType_UNKNOWN {
+			break
+		}
+		if f, exact, ok := protoNumber(value.UnknownValue.GetValue()); ok {
+			recordExact("", nil, f, exact, nil)
+			return
+		}
+	case *openmetrics.MetricPoint_GaugeValue:
+		if mt != openmetrics.MetricType_GAUGE {
+			break
+		}
+		if f, exact, ok := protoNumber(value.GaugeValue.GetValue()); ok {
+			recordExact("", nil, f, exact, nil)
+			return
+		}
+	case *openmetrics.MetricPoint_CounterValue:
+		if mt != openmetrics.MetricType_COUNTER {
+			break
+		}
+		if f, exact, ok := protoNumber(value.CounterValue.GetTotal()); ok {
+			recordExact("_total", nil, f, exact, value.CounterValue.GetExemplar())
+			recordCreated(value.CounterValue.GetCreated())
+			return
+		}
+	case *openmetrics.MetricPoint_HistogramValue:
+		if mt != openmetrics.MetricType_HISTOGRAM && mt != openmetrics.MetricType_GAUGE_HISTOGRAM {
+			break
+		}
+		hv := value.HistogramValue
+		for _, b := range hv.GetBuckets() {
+			recordCount("_bucket", labels.Labels{{Name: labels.BucketLabel, Value: formatThreshold(b.GetUpperBound())}},
+				b.GetCount(), b.GetExemplar())
+		}
+		countSuffix, sumSuffix := "_count", "_sum"
+		if mt == openmetrics.MetricType_GAUGE_HISTOGRAM {
+			countSuffix, sumSuffix = "_gcount", "_gsum"
+		}
+		sum, exactSum, sumFound := protoNumber(hv.GetSum())
+		if sumFound || hv.GetCount() != 0 {
+			recordCount(countSuffix, nil, hv.GetCount(), nil)
+		}
+		if sumFound {
+			recordExact(sumSuffix, nil, sum, exactSum, nil)
+		}
+		// Gauge histograms have no _created sample in the text format, so
+		// their created timestamp is ignored like the model encoder does.
+		if mt == openmetrics.MetricType_HISTOGRAM {
+			recordCreated(hv.GetCreated())
+		}
+		return
+	case *openmetrics.MetricPoint_StateSetValue:
+		if mt != openmetrics.MetricType_STATE_SET {
+			break
+		}
+		for _, s := range value.StateSetValue.GetStates() {
+			var f float64
+			if s.GetEnabled() {
+				f = 1
+			}
+			record("", labels.Labels{{Name: mfn, Value: s.GetName()}}, f, nil)
+		}
+		return
+	case *openmetrics.MetricPoint_InfoValue:
+		if mt != openmetrics.MetricType_INFO {
+			break
+		}
+		info, ok := v.protoLabels(mfn, value.InfoValue.GetInfo())
+		if ok {
+			record("_info", info, 1, nil)
+		}
+		return
+	case *openmetrics.MetricPoint_SummaryValue:
+		if mt != openmetrics.MetricType_SUMMARY {
+			break
+		}
+		sv := value.SummaryValue
+		for _, q := range sv.GetQuantile() {
+			record("", labels.Labels{{Name: "quantile", Value: formatThreshold(q.GetQuantile())}}, q.GetValue(), nil)
+		}
+		sum, exactSum, sumFound := protoNumber(sv.GetSum())
+		if sumFound || sv.GetCount() != 0 {
+			recordCount("_count", nil, sv.GetCount(), nil)
+		}
+		if sumFound {
+			recordExact("_sum", nil, sum, exactSum, nil)
+		}
+		recordCreated(sv.GetCreated())
+		return
+	}
+
+	metricLset := labels.New(append(labels.Labels{{Name: labels.MetricName, Value: mfn}}, lset...)...)
+	v.addViolation(Violation{MetricFamily: mfn, Labels: metricLset, Timestamp: t}, errMustProtoValueMatchType.withMessage(
+		"MetricPoint value %T is missing or does not match the MetricFamily type %v", mp.GetValue(), mt))
+}
+
+// protoNumber returns the value of a number oneof, and the integer value if
+// float64 cannot represent it exactly. It returns false if the value is not set.
+func protoNumber(value interface{}) (float64, *big.Int, bool) {
+	switch n := value.(type) {
+	case *openmetrics.UnknownValue_DoubleValue:
+		return n.DoubleValue, nil, true
+	case *openmetrics.UnknownValue_IntValue:
+		return float64(n.IntValue), inexactInt(n.IntValue), true
+	case *openmetrics.GaugeValue_DoubleValue:
+		return n.DoubleValue, nil, true
+	case *openmetrics.GaugeValue_IntValue:
+		return float64(n.IntValue), inexactInt(n.IntValue), true
+	case *openmetrics.CounterValue_DoubleValue:
+		return n.DoubleValue, nil, true
+	case *openmetrics.CounterValue_IntValue:
+		return float64(n.IntValue), inexactUint(n.IntValue), true
+	case *openmetrics.HistogramValue_DoubleValue:
+		return n.DoubleValue, nil, true
+	case *openmetrics.HistogramValue_IntValue:
+		return float64(n.IntValue), inexactInt(n.IntValue), true
+	case *openmetrics.SummaryValue_DoubleValue:
+		return n.DoubleValue, nil, true
+	case *openmetrics.SummaryValue_IntValue:
+		return float64(n.IntValue), inexactInt(n.IntValue), true
+	}
+	return 0, nil, false
+}
+
+func protoExemplar(e *openmetrics.Exemplar) *exemplar.Exemplar {
+	if e == nil {
+		return nil
+	}
+	res := &exemplar.Exemplar{Value: e.GetValue()}
+	for _, l := range e.GetLabel() {
+		res.Labels = append(res.Labels, labels.Label{Name: l.GetName(), Value: l.GetValue()})
+	}
+	res.Labels = labels.New(res.Labels...)
+	if e.GetTimestamp() != nil {
+		res.Ts, res.HasTs = protoTimestamp(e.GetTimestamp()), true
+	}
+	return res
+}
+
+// protoTimestamp returns the timestamp in milliseconds.
+func protoTimestamp(ts *timestamppb.Timestamp) int64 {
+	return timestamp.FromTime(ts.AsTime())
+}
+
+// formatThreshold formats a bucket threshold or a quantile as in the text format.
+func formatThreshold(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,251 @@
+package validator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/OpenObservability/OpenMetrics/src/proto/openmetrics"
+)
+
+func TestValidateProto(t *testing.T) {
+	tcs := []struct {
+		name        string
+		metricSets  []*openmetrics.MetricSet
+		expectedErr error
+	}{
+		{
+			name: "good_metric_set",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("a", openmetrics.MetricType_COUNTER, "", protoPoint(&openmetrics.MetricPoint_CounterValue{
+						CounterValue: &openmetrics.CounterValue{
+							Total:   &openmetrics.CounterValue_IntValue{IntValue: 1},
+							Created: &timestamppb.Timestamp{Seconds: 0},
+						},
+					})),
+					protoFamily("b_seconds", openmetrics.MetricType_HISTOGRAM, "seconds", protoPoint(&openmetrics.MetricPoint_HistogramValue{
+						HistogramValue: &openmetrics.HistogramValue{
+							Sum:   &openmetrics.HistogramValue_DoubleValue{DoubleValue: 1.5},
+							Count: 2,
+							Buckets: []*openmetrics.HistogramValue_Bucket{
+								{UpperBound: 1, Count: 1, Exemplar: &openmetrics.Exemplar{Value: 0.5}},
+								{UpperBound: protoInf, Count: 2},
+							},
+						},
+					})),
+					protoFamily("c", openmetrics.MetricType_STATE_SET, "", protoPoint(&openmetrics.MetricPoint_StateSetValue{
+						StateSetValue: &openmetrics.StateSetValue{
+							States: []*openmetrics.StateSetValue_State{
+								{Name: "x", Enabled: true},
+								{Name: "y"},
+							},
+						},
+					})),
+				},
+			}},
+		},
+		{
+			name: "bad_value_does_not_match_type",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("a", openmetrics.MetricType_COUNTER, "", protoPoint(&openmetrics.MetricPoint_GaugeValue{
+						GaugeValue: &openmetrics.GaugeValue{Value: &openmetrics.GaugeValue_DoubleValue{DoubleValue: 1}},
+					})),
+				},
+			}},
+			expectedErr: errMustProtoValueMatchType,
+		},
+		{
+			name: "bad_missing_value",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("a", openmetrics.MetricType_GAUGE, "", protoPoint(&openmetrics.MetricPoint_GaugeValue{
+						GaugeValue: &openmetrics.GaugeValue{},
+					})),
+				},
+			}},
+			expectedErr: errMustProtoValueMatchType,
+		},
+		{
+			name: "bad_invalid_name",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("a-b", openmetrics.MetricType_GAUGE, "", protoPoint(&openmetrics.MetricPoint_GaugeValue{
+						GaugeValue: &openmetrics.GaugeValue{Value: &openmetrics.GaugeValue_IntValue{IntValue: 1}},
+					})),
+				},
+			}},
+			expectedErr: errMustProtoNameBeValid,
+		},
+		{
+			// The text parser rejects such units, only the protobuf format
+			// reaches the rule.
+			name: "bad_unit_suffix_without_underscore",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("fooseconds", openmetrics.MetricType_GAUGE, "seconds", protoPoint(&openmetrics.MetricPoint_GaugeValue{
+						GaugeValue: &openmetrics.GaugeValue{Value: &openmetrics.GaugeValue_IntValue{IntValue: 1}},
+					})),
+				},
+			}},
+			expectedErr: errMustUnitBeNameSuffix,
+		},
+		{
+			name: "bad_counter_negative",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("a", openmetrics.MetricType_COUNTER, "", protoPoint(&openmetrics.MetricPoint_CounterValue{
+						CounterValue: &openmetrics.CounterValue{
+							Total: &openmetrics.CounterValue_DoubleValue{DoubleValue: -1},
+						},
+					})),
+				},
+			}},
+			expectedErr: errCounterValueNegative,
+		},
+		{
+			name: "bad_histogram_buckets_not_in_order",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("a", openmetrics.MetricType_HISTOGRAM, "", protoPoint(&openmetrics.MetricPoint_HistogramValue{
+						HistogramValue: &openmetrics.HistogramValue{
+							Buckets: []*openmetrics.HistogramValue_Bucket{
+								{UpperBound: protoInf, Count: 2},
+								{UpperBound: 1, Count: 1},
+							},
+						},
+					})),
+				},
+			}},
+			expectedErr: errMustHistogramBucketsInOrder,
+		},
+		{
+			name: "bad_histogram_missing_+Inf_bucket",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("a", openmetrics.MetricType_GAUGE_HISTOGRAM, "", protoPoint(&openmetrics.MetricPoint_HistogramValue{
+						HistogramValue: &openmetrics.HistogramValue{
+							Buckets: []*openmetrics.HistogramValue_Bucket{{UpperBound: 1, Count: 1}},
+						},
+					})),
+				},
+			}},
+			expectedErr: errMustContainPositiveInfBucket,
+		},
+		{
+			name: "good_gauge_histogram_with_created",
+			metricSets: []*openmetrics.MetricSet{{
+				MetricFamilies: []*openmetrics.MetricFamily{
+					protoFamily("g", openmetrics.MetricType_GAUGE_HISTOGRAM, "", protoPoint(&openmetrics.MetricPoint_HistogramValue{
+						HistogramValue: &openmetrics.HistogramValue{
+							Sum:     &openmetrics.HistogramValue_DoubleValue{DoubleValue: 1.5},
+							Count:   2,
+							Created: &timestamppb.Timestamp{Seconds: 0},
+							Buckets: []*openmetrics.HistogramValue_Bucket{
+								{UpperBound: 1, Count: 1},
+								{UpperBound: protoInf, Count: 2},
+							},
+						},
+					})),
+				},
+			}},
+		},
+		{
+			name: "bad_created_decrease",
+			metricSets: []*openmetrics.MetricSet{
+				{
+					MetricFamilies: []*openmetrics.MetricFamily{
+						protoFamily("a", openmetrics.MetricType_SUMMARY, "", protoPoint(&openmetrics.MetricPoint_SummaryValue{
+							SummaryValue: &openmetrics.SummaryValue{
+								Sum:     &openmetrics.SummaryValue_IntValue{IntValue: 1},
+								Count:   1,
+								Created: &timestamppb.Timestamp{Seconds: 1},
+							},
+						})),
+					},
+				},
+				{
+					MetricFamilies: []*openmetrics.MetricFamily{
+						protoFamily("a", openmetrics.MetricType_SUMMARY, "", protoPoint(&openmetrics.MetricPoint_SummaryValue{
+							SummaryValue: &openmetrics.SummaryValue{
+								Sum:     &openmetrics.SummaryValue_IntValue{IntValue: 1},
+								Count:   1,
+								Created: &timestamppb.Timestamp{Seconds: 0},
+							},
+						})),
+					},
+				},
+			},
+			expectedErr: errMustNotCreatedDecrease,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var exports []string
+			for _, ms := range tc.metricSets {
+				b, err := proto.Marshal(ms)
+				require.NoError(t, err)
+				exports = append(exports, string(b))
+			}
+			v := NewValidator(ErrorLevelShould, WithClock(testNowFn()), WithFormat(FormatProtobuf))
+			var err error
+			for _, export := range exports {
+				err = v.Validate([]byte(export))
+			}
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestValidateProtoDecodeError(t *testing.T) {
+	v := NewValidator(ErrorLevelMust)
+	require.Error(t, v.ValidateProto([]byte("not a metric set")))
+
+	report := v.Report()
+	require.Len(t, report.Violations, 1)
+	require.Equal(t, RuleID("parse"), report.Violations[0].Rule)
+	require.False(t, report.Violations[0].Pos.IsValid())
+}
+
+var protoInf = math.Inf(1)
+
+func protoFamily(name string, mt openmetrics.MetricType, unit string, points ...*openmetrics.MetricPoint) *openmetrics.MetricFamily {
+	return &openmetrics.MetricFamily{
+		Name: name,
+		Type: mt,
+		Unit: unit,
+		Metrics: []*openmetrics.Metric{{
+			MetricPoints: points,
+		}},
+	}
+}
+
+func protoPoint(value interface{}) *openmetrics.MetricPoint {
+	mp := &openmetrics.MetricPoint{}
+	switch value := value.(type) {
+	case *openmetrics.MetricPoint_UnknownValue:
+		mp.Value = value
+	case *openmetrics.MetricPoint_GaugeValue:
+		mp.Value = value
+	case *openmetrics.MetricPoint_CounterValue:
+		mp.Value = value
+	case *openmetrics.MetricPoint_HistogramValue:
+		mp.Value = value
+	case *openmetrics.MetricPoint_StateSetValue:
+		mp.Value = value
+	case *openmetrics.MetricPoint_InfoValue:
+		mp.Value = value
+	case *openmetrics.MetricPoint_SummaryValue:
+		mp.Value = value
+	}
+	return mp
+}
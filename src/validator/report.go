@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"go.uber.org/multierr"
+)
+
+// Violation is a single breach of an OpenMetrics rule found during validation.
+type Violation struct {
+	// Rule is the stable identifier of the rule that was violated.
+	Rule RuleID
+	// Level is the category of the rule, e.g. "MUST" or "SHOULD".
+	Level ErrorLevel
+	// Pos is the position of the offending entry in the exposition. Violations
+	// only detectable once the whole metric family is read point to the first
+	// entry of the family. It is not valid when the offending entry is no
+	// longer present in the exposition, e.g. for a disappeared metric family.
+	Pos Position
+	// MetricFamily is the name of the metric family the violation belongs to,
+	// it is empty for violations which are not specific to a metric family.
+	MetricFamily string
+	// Labels is the full label set of the offending sample including the
+	// metric name, it is nil for violations of a metric family or metric set.
+	Labels labels.Labels
+	// Value, Timestamp and Exemplar describe the offending sample and are
+	// only set when Labels is set.
+	Value     float64
+	Timestamp int64
+	Exemplar  *exemplar.Exemplar
+	// Disappeared and Appeared are the label sets of the series which
+	// disappeared from the metric family since the last exposition, and of
+	// the ones which newly appeared in it. A series is a MetricPoint named
+	// after the metric family, e.g. a histogram with all its samples. They
+	// are only set for violations of disappeared or appeared series, which
+	// are reported once per metric family.
+	Disappeared []labels.Labels
+	Appeared    []labels.Labels
+	// Err is the underlying error, it matches the sentinel error of the rule.
+	Err error
+}
+
+func (vi Violation) Error() string {
+	switch {
+	case vi.Labels != nil:
+		m := metric{
+			lset:      vi.Labels,
+			value:     vi.Value,
+			timestamp: vi.Timestamp,
+			exemplar:  vi.Exemplar,
+		}
+		return fmt.Sprintf("error for metric %s: %v", m.String(), vi.Err)
+	case vi.MetricFamily != "":
+		return fmt.Sprintf("error for metric family %s: %v", vi.MetricFamily, vi.Err)
+	default:
+		return vi.Err.Error()
+	}
+}
+
+func (vi Violation) Unwrap() error {
+	return vi.Err
+}
+
+// Report is the list of violations found by a validator.
+type Report struct {
+	Violations []Violation
+	// Truncated is set when the validation was cut short by a limit of the
+	// validator, see WithMaxErrors and WithMaxSeries. It is not a violation
+	// of the exposition. OmittedViolations is the number of violations which
+	// are not reported, and IgnoredSamples the number of samples of the series
+	// which are not validated.
+	Truncated         bool
+	OmittedViolations int
+	IgnoredSamples    int
+}
+
+// Err returns all the violations combined as a single error, or nil if there
+// are no violations.
+func (r Report) Err() error {
+	var err error
+	for _, vi := range r.Violations {
+		err = multierr.Append(err, vi)
+	}
+	return err
+}
+
+// ruleOf returns the rule and the level of the error, errors which do not
+// belong to any rule are treated as MUST level errors.
+func ruleOf(err error) (RuleID, ErrorLevel) {
+	var ewl errorWithLevel
+	if errors.As(err, &ewl) {
+		return ewl.rule, ewl.level
+	}
+	return "", ErrorLevelMust
+}
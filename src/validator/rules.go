@@ -0,0 +1,203 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// _rules is the registry of the rules that can be disabled or re-levelled.
+// Parse errors are not part of it since they are always reported.
+var _rules = newRuleRegistry(
+	errExemplar,
+	errExemplarLabelsTooLong,
+	errMustExemplarBeInBucketRange,
+	errMustNotMixTimestampPresense,
+	errMustTimestampIncrease,
+	errMustLabelNamesBeUnique,
+	errMustMetricHaveName,
+	errMetricTypeAlreadySet,
+	errUnitAlreadySet,
+	errHelpAlreadySet,
+	errMetadataNameChanged,
+	errMustNotMetricFamiliesInterleave,
+	errMustSampleSuffixBeValid,
+	errMustNotMetricFamilyNamesClash,
+	errMustUnitBeNameSuffix,
+	errMustNotCounterValueDecrease,
+	errMustNotHistogramValueDecrease,
+	errMustNotSummaryValueDecrease,
+	errMustCounterValueBeValid,
+	errMustCounterUnitPrecedeTotal,
+	errCounterValueNaN,
+	errCounterValueNegative,
+	errMustContainPositiveInfBucket,
+	errMustHistogramBucketsInOrder,
+	errMustHistogramBucketValuesIncrease,
+	errMustBucketLabelBeValid,
+	errMustHistogramHaveSumAndCount,
+	errMustHistogramNotHaveSumAndNegative,
+	errMustHistogramCountEqualInfBucket,
+	errMustHistogramValueBeInteger,
+	errGaugeHistogramBucketValueNaN,
+	errGaugeHistogramBucketValueNegative,
+	errGaugeHistogramGSumValueNaN,
+	errMustGaugeHistogramBucketsInOrder,
+	errMustGaugeHistogramBucketValuesIncrease,
+	errMustGaugeHistogramGCountEqualInfBucket,
+	errMustGaugeHistogramBucketValueBeInteger,
+	errMustGaugeHistogramNotHaveGSumAndNegative,
+	errMustGaugeHistogramHaveGSumAndGCountOrNeither,
+	errMustSummaryQuantileBeValid,
+	errMustSummaryQuantileBeBetweenZeroAndOne,
+	errMustNotSummaryQuantileValueBeNegative,
+	errInvalidSummaryCountAndSum,
+	errMustStateSetContainLabel,
+	errMustNoUnitForStateSet,
+	errMustStateSetHaveOneSamplePerState,
+	errMustEnumStateSetHaveOneTrueState,
+	errInvalidStateSetValue,
+	errMustNoUnitForInfo,
+	errInvalidInfoValue,
+	errMustProtoNameBeValid,
+	errMustProtoValueMatchType,
+	errMustNotCreatedDecrease,
+	errShouldCreatedChangeOnlyOnReset,
+	errShouldNotCreatedBeAfterTimestamp,
+	errShouldNotMetricsDisappear,
+	errShouldNotDuplicateLabel,
+	errShouldStateSetStatesBeStable,
+	errShouldExemplarBeAbovePreviousBucket,
+	errShouldNotExemplarExceedCounterIncrease,
+	errShouldNotExemplarTimestampBeInFuture,
+	errShouldNotExemplarTimestampDecrease,
+	errShouldNotTimestampBeInFuture,
+	errShouldNotTimestampBeInPast,
+	errShouldTimestampBeInExpectedUnit,
+	errShouldNotLiveTargetExposeTimestamps,
+	errShouldValueBeExactlyRepresentable,
+	errLintSnakeCase,
+	errLintBaseUnit,
+	errLintTotalSuffix,
+	errLintGaugeSuffix,
+	errLintHelp,
+	errLintTypeInName,
+)
+
+// Rule describes a validation rule.
+type Rule struct {
+	ID          RuleID
+	Level       ErrorLevel
+	Description string
+}
+
+type ruleRegistry map[RuleID]Rule
+
+func newRuleRegistry(errs ...errorWithLevel) ruleRegistry {
+	r := make(ruleRegistry, len(errs))
+	for _, e := range errs {
+		if _, ok := r[e.rule]; ok {
+			panic(fmt.Sprintf("rule %q registered twice", e.rule))
+		}
+		r[e.rule] = Rule{
+			ID:          e.rule,
+			Level:       e.level,
+			Description: e.err.Error(),
+		}
+	}
+	return r
+}
+
+// AllRules returns all the rules that can be configured, ordered by ID.
+func AllRules() []Rule {
+	rules := make([]Rule, 0, len(_rules))
+	for _, r := range _rules {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+// Rules configures which rules are enabled and at which level they are
+// reported. The zero value enables all the rules at their default level.
+type Rules struct {
+	disabled map[RuleID]bool
+	levels   map[RuleID]ErrorLevel
+}
+
+// Disable disables the rule.
+func (r *Rules) Disable(id RuleID) error {
+	if _, ok := _rules[id]; !ok {
+		return fmt.Errorf("unknown rule %q", id)
+	}
+	if r.disabled == nil {
+		r.disabled = make(map[RuleID]bool)
+	}
+	r.disabled[id] = true
+	return nil
+}
+
+// SetLevel overrides the level the rule is reported at.
+func (r *Rules) SetLevel(id RuleID, level ErrorLevel) error {
+	if _, ok := _rules[id]; !ok {
+		return fmt.Errorf("unknown rule %q", id)
+	}
+	if r.levels == nil {
+		r.levels = make(map[RuleID]ErrorLevel)
+	}
+	r.levels[id] = level
+	return nil
+}
+
+// level returns the level the rule is reported at and whether it is enabled.
+// Errors which are not registered rules are always enabled.
+func (r Rules) level(id RuleID, def ErrorLevel) (ErrorLevel, bool) {
+	if _, ok := _rules[id]; !ok {
+		return def, true
+	}
+	if r.disabled[id] {
+		return def, false
+	}
+	if level, ok := r.levels[id]; ok {
+		return level, true
+	}
+	return def, true
+}
+
+// ParseRules creates Rules from a comma separated list of rules to disable,
+// e.g. "counter.monotonic,histogram.inf-bucket", and a comma separated list
+// of rule levels, e.g. "labels.duplicated-on-all-series=must".
+func ParseRules(disabled, levels string) (Rules, error) {
+	var r Rules
+	for _, id := range splitList(disabled) {
+		if err := r.Disable(RuleID(id)); err != nil {
+			return Rules{}, err
+		}
+	}
+	for _, kv := range splitList(levels) {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return Rules{}, fmt.Errorf("invalid rule level %q, expected <rule>=<level>", kv)
+		}
+		el, err := NewErrorLevel(parts[1])
+		if err != nil {
+			return Rules{}, err
+		}
+		if err := r.SetLevel(RuleID(parts[0]), el); err != nil {
+			return Rules{}, err
+		}
+	}
+	return r, nil
+}
+
+func splitList(s string) []string {
+	var res []string
+	for _, elem := range strings.Split(s, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			res = append(res, elem)
+		}
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWithRules(t *testing.T) {
+	str := `# TYPE a1 counter
+a1_total{bar="baz"} 1
+# TYPE a2 counter
+a2_total{bar="baz"} 1
+a1_total{bar="baz"} 1
+# EOF`
+
+	tcs := []struct {
+		name          string
+		level         ErrorLevel
+		disabled      string
+		levels        string
+		expectedRules []RuleID
+	}{
+		{
+			name:          "default",
+			level:         ErrorLevelShould,
+			expectedRules: []RuleID{"family.interleaved", "labels.duplicated-on-all-series"},
+		},
+		{
+			name:          "disabled",
+			level:         ErrorLevelShould,
+			disabled:      "family.interleaved",
+			expectedRules: []RuleID{"labels.duplicated-on-all-series"},
+		},
+		{
+			name:          "relevelled_to_should",
+			level:         ErrorLevelMust,
+			levels:        "family.interleaved=should",
+			expectedRules: nil,
+		},
+		{
+			name:          "relevelled_to_must",
+			level:         ErrorLevelMust,
+			levels:        "labels.duplicated-on-all-series=must",
+			expectedRules: []RuleID{"family.interleaved", "labels.duplicated-on-all-series"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := ParseRules(tc.disabled, tc.levels)
+			require.NoError(t, err)
+
+			v := NewValidator(tc.level, WithRules(rules))
+			err = v.Validate([]byte(str))
+			var ruleIDs []RuleID
+			for _, vi := range v.Report().Violations {
+				ruleIDs = append(ruleIDs, vi.Rule)
+			}
+			require.Equal(t, tc.expectedRules, ruleIDs)
+			if len(tc.expectedRules) == 0 {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWithRulesLevel(t *testing.T) {
+	var rules Rules
+	require.NoError(t, rules.SetLevel("family.interleaved", ErrorLevelShould))
+
+	v := NewValidator(ErrorLevelShould, WithRules(rules))
+	require.Error(t, v.Validate([]byte(`a 1
+b 1
+a{c="d"} 1
+# EOF`)))
+	report := v.Report()
+	require.Len(t, report.Violations, 1)
+	require.Equal(t, ErrorLevelShould, report.Violations[0].Level)
+}
+
+func TestParseRules(t *testing.T) {
+	_, err := ParseRules("counter.monotonic, histogram.inf-bucket", "labels.duplicated-on-all-series=must")
+	require.NoError(t, err)
+
+	_, err = ParseRules("unknown", "")
+	require.EqualError(t, err, `unknown rule "unknown"`)
+
+	_, err = ParseRules("", "counter.monotonic")
+	require.EqualError(t, err, `invalid rule level "counter.monotonic", expected <rule>=<level>`)
+
+	_, err = ParseRules("", "counter.monotonic=may")
+	require.EqualError(t, err, `unknown error level "may"`)
+
+	_, err = ParseRules("parse", "")
+	require.EqualError(t, err, `unknown rule "parse"`)
+}
+
+func TestAllRules(t *testing.T) {
+	rules := AllRules()
+	require.Len(t, rules, len(_rules))
+	for i := 1; i < len(rules); i++ {
+		require.Less(t, string(rules[i-1].ID), string(rules[i].ID))
+	}
+}
@@ -0,0 +1,166 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// _stateVersion is the version of the serialized state, states of other
+// versions are rejected.
+const _stateVersion = 1
+
+// state is the serialized history of a validator, the metric families of the
+// last exposition. Floats are serialized as strings since JSON has no NaN
+// or infinities, and integers which float64 rounds as their exact value.
+type state struct {
+	Version        int                    `json:"version"`
+	MetricFamilies map[string]familyState `json:"metric_families"`
+}
+
+type familyState struct {
+	Type    textparse.MetricType `json:"type"`
+	Help    string               `json:"help,omitempty"`
+	Unit    string               `json:"unit,omitempty"`
+	Metrics []metricState        `json:"metrics"`
+	Created []metricState        `json:"created,omitempty"`
+}
+
+type metricState struct {
+	Point     string         `json:"point"`
+	Labels    labels.Labels  `json:"labels"`
+	Timestamp int64          `json:"timestamp"`
+	Value     string         `json:"value"`
+	Exemplar  *exemplarState `json:"exemplar,omitempty"`
+}
+
+type exemplarState struct {
+	Labels    labels.Labels `json:"labels"`
+	Value     string        `json:"value"`
+	Timestamp *int64        `json:"timestamp,omitempty"`
+}
+
+// SaveState writes the history of the validator, i.e. the metrics of the last
+// exposition the next one is compared with, so that it can be restored with
+// LoadState, e.g. after a restart.
+func (v *OpenMetricsValidator) SaveState(w io.Writer) error {
+	s := state{
+		Version:        _stateVersion,
+		MetricFamilies: make(map[string]familyState, len(v.lastMetricSet)),
+	}
+	for mfn, mf := range v.lastMetricSet {
+		fs := familyState{Type: mf.MetricType()}
+		if mf.help != nil {
+			fs.Help = *mf.help
+		}
+		if mf.unit != nil {
+			fs.Unit = *mf.unit
+		}
+		for _, m := range mf.metrics {
+			fs.Metrics = append(fs.Metrics, newMetricState(m))
+		}
+		for _, m := range mf.created {
+			fs.Created = append(fs.Created, newMetricState(m))
+		}
+		// Sort the metrics so the same history is always written the same way.
+		sort.Slice(fs.Metrics, func(i, j int) bool {
+			return labels.Compare(fs.Metrics[i].Labels, fs.Metrics[j].Labels) < 0
+		})
+		sort.Slice(fs.Created, func(i, j int) bool {
+			return fs.Created[i].Point < fs.Created[j].Point
+		})
+		s.MetricFamilies[mfn] = fs
+	}
+	return json.NewEncoder(w).Encode(s)
+}
+
+// LoadState restores the history of the validator written by SaveState, the
+// next exposition is compared with it.
+func (v *OpenMetricsValidator) LoadState(r io.Reader) error {
+	var s state
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("could not decode the validator state: %w", err)
+	}
+	if s.Version != _stateVersion {
+		return fmt.Errorf("unsupported validator state version %d, expected %d", s.Version, _stateVersion)
+	}
+	metricSet := make(map[string]*metricFamily, len(s.MetricFamilies))
+	for mfn, fs := range s.MetricFamilies {
+		mf := newMetricFamily()
+		mt, help, unit := fs.Type, fs.Help, fs.Unit
+		mf.metricType, mf.help, mf.unit = &mt, &help, &unit
+		for _, ms := range fs.Metrics {
+			m, err := ms.metric(mfn)
+			if err != nil {
+				return err
+			}
+			mf.metrics[labelKey(m.lset)] = m
+		}
+		for _, ms := range fs.Created {
+			m, err := ms.metric(mfn)
+			if err != nil {
+				return err
+			}
+			mf.created[m.point] = m
+		}
+		metricSet[mfn] = mf
+	}
+	v.lastMetricSet = metricSet
+	return nil
+}
+
+func newMetricState(m metric) metricState {
+	ms := metricState{
+		Point:     m.point,
+		Labels:    m.lset,
+		Timestamp: m.timestamp,
+		Value:     formatStateFloat(m.value),
+	}
+	if m.exactValue != nil {
+		ms.Value = m.exactValue.String()
+	}
+	if e := m.exemplar; e != nil {
+		ms.Exemplar = &exemplarState{Labels: e.Labels, Value: formatStateFloat(e.Value)}
+		if e.HasTs {
+			ts := e.Ts
+			ms.Exemplar.Timestamp = &ts
+		}
+	}
+	return ms
+}
+
+func (ms metricState) metric(mfn string) (metric, error) {
+	value, err := strconv.ParseFloat(ms.Value, 64)
+	if err != nil {
+		return metric{}, fmt.Errorf("invalid value of metric %s in the validator state: %w", ms.Labels, err)
+	}
+	m := metric{
+		mfn:        mfn,
+		point:      ms.Point,
+		lset:       ms.Labels,
+		timestamp:  ms.Timestamp,
+		value:      value,
+		exactValue: parseInexactInt([]byte(ms.Value)),
+	}
+	if es := ms.Exemplar; es != nil {
+		value, err := strconv.ParseFloat(es.Value, 64)
+		if err != nil {
+			return metric{}, fmt.Errorf("invalid exemplar value of metric %s in the validator state: %w", ms.Labels, err)
+		}
+		m.exemplar = &exemplar.Exemplar{Labels: es.Labels, Value: value}
+		if es.Timestamp != nil {
+			m.exemplar.Ts, m.exemplar.HasTs = *es.Timestamp, true
+		}
+	}
+	return m, nil
+}
+
+func formatStateFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
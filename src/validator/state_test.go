@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoadState(t *testing.T) {
+	v := testValidator(ErrorLevelShould)
+	require.NoError(t, v.Validate([]byte(`# TYPE a counter
+# HELP a help
+a_total{b="1"} 5 # {trace_id="x"} 1 1
+a_created{b="1"} 1
+# TYPE c gauge
+# HELP c help
+c{b="2"} NaN
+# EOF
+`)))
+	var buf bytes.Buffer
+	require.NoError(t, v.SaveState(&buf))
+
+	var again bytes.Buffer
+	require.NoError(t, v.SaveState(&again))
+	require.Equal(t, buf.String(), again.String())
+
+	restored := testValidator(ErrorLevelShould)
+	require.NoError(t, restored.LoadState(bytes.NewReader(buf.Bytes())))
+	var resaved bytes.Buffer
+	require.NoError(t, restored.SaveState(&resaved))
+	require.Equal(t, buf.String(), resaved.String())
+
+	// The counter decrease is only detected with the restored history.
+	err := restored.Validate([]byte(`# TYPE a counter
+# HELP a help
+a_total{b="1"} 3
+a_created{b="1"} 1
+# TYPE c gauge
+# HELP c help
+c{b="2"} 1
+# EOF
+`))
+	require.True(t, errors.Is(err, errMustNotCounterValueDecrease))
+}
+
+func TestLoadStateInvalid(t *testing.T) {
+	v := NewValidator(ErrorLevelMust)
+	require.EqualError(t, v.LoadState(strings.NewReader(`{"version": 2}`)),
+		"unsupported validator state version 2, expected 1")
+	require.Error(t, v.LoadState(strings.NewReader(`{`)))
+}
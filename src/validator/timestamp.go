@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errShouldNotTimestampBeInFuture = errorWithLevel{
+		rule:  "timestamp.future",
+		err:   errors.New("sample timestamp SHOULD NOT be in the future relative to the scrape"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldNotTimestampBeInPast = errorWithLevel{
+		rule:  "timestamp.past",
+		err:   errors.New("sample timestamp SHOULD NOT be too far in the past relative to the scrape"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldTimestampBeInExpectedUnit = errorWithLevel{
+		rule:  "timestamp.unit",
+		err:   errors.New("sample timestamps are in seconds in OpenMetrics and in milliseconds in the Prometheus text format"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldNotLiveTargetExposeTimestamps = errorWithLevel{
+		rule:  "timestamp.live-target",
+		err:   errors.New("expositions of live targets SHOULD NOT have timestamps"),
+		level: ErrorLevelShould,
+	}
+)
+
+// _minPlausibleScrapeTime is the time before which the clock of the validator
+// is assumed not to be a wall clock, e.g. in tests, so the unit of the
+// timestamps is not guessed.
+var _minPlausibleScrapeTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+
+// validateTimestamp checks the explicit timestamp of the sample against the
+// time of the scrape.
+func (v *OpenMetricsValidator) validateTimestamp(cur metric) {
+	if v.scrapeTime >= _minPlausibleScrapeTime {
+		// A timestamp in the wrong unit is about a thousand times off.
+		ratio := float64(cur.timestamp) / float64(v.scrapeTime)
+		switch {
+		case v.format != FormatPrometheusText && ratio >= 100 && ratio <= 10000:
+			v.addMetricErrorAt(cur, cur.columns.timestamp, errShouldTimestampBeInExpectedUnit.withMessage(
+				"sample timestamp seems to be in milliseconds rather than in seconds"))
+			return
+		case v.format == FormatPrometheusText && ratio >= 0.0001 && ratio <= 0.01:
+			v.addMetricErrorAt(cur, cur.columns.timestamp, errShouldTimestampBeInExpectedUnit.withMessage(
+				"sample timestamp seems to be in seconds rather than in milliseconds"))
+			return
+		}
+	}
+	if v.maxTimestampFuture > 0 && cur.timestamp > v.scrapeTime+v.maxTimestampFuture.Milliseconds() {
+		v.addMetricErrorAt(cur, cur.columns.timestamp, errShouldNotTimestampBeInFuture.withMessage(
+			"%v: by more than %v", errShouldNotTimestampBeInFuture.err, v.maxTimestampFuture))
+	}
+	if v.maxTimestampPast > 0 && cur.timestamp < v.scrapeTime-v.maxTimestampPast.Milliseconds() {
+		v.addMetricErrorAt(cur, cur.columns.timestamp, errShouldNotTimestampBeInPast.withMessage(
+			"%v: by more than %v", errShouldNotTimestampBeInPast.err, v.maxTimestampPast))
+	}
+}
+
+// exemplarFutureTolerance returns how far exemplar timestamps may be in the
+// future, sample and exemplar timestamps share the future tolerance if it is
+// set.
+func (v *OpenMetricsValidator) exemplarFutureTolerance() time.Duration {
+	if v.maxTimestampFuture > 0 {
+		return v.maxTimestampFuture
+	}
+	return _exemplarFutureTolerance
+}
+
+// validateMetricFamilyTimestamps makes sure that the metric family of a live
+// target has no timestamps.
+func (v *OpenMetricsValidator) validateMetricFamilyTimestamps(mfn string, cur *metricFamily) {
+	if v.liveTarget && cur.metricWithTimestampRecorded {
+		v.addMetricFamilyError(mfn, cur.pos, errShouldNotLiveTargetExposeTimestamps)
+	}
+}
@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTimestamp(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	seconds := func(d time.Duration) string {
+		return fmt.Sprint(now.Add(d).Unix())
+	}
+	tcs := []struct {
+		name          string
+		format        Format
+		opts          []Option
+		timestamp     string
+		expectedRules []RuleID
+	}{
+		{
+			name:      "good_timestamp",
+			timestamp: seconds(-time.Minute),
+			opts:      []Option{WithTimestampTolerance(time.Minute, time.Hour)},
+		},
+		{
+			name:          "bad_future_timestamp",
+			timestamp:     seconds(2 * time.Minute),
+			opts:          []Option{WithTimestampTolerance(time.Minute, time.Hour)},
+			expectedRules: []RuleID{"timestamp.future"},
+		},
+		{
+			name:          "bad_past_timestamp",
+			timestamp:     seconds(-2 * time.Hour),
+			opts:          []Option{WithTimestampTolerance(time.Minute, time.Hour)},
+			expectedRules: []RuleID{"timestamp.past"},
+		},
+		{
+			name:      "good_tolerance_disabled",
+			timestamp: seconds(-24 * time.Hour),
+		},
+		{
+			name:          "bad_timestamp_in_milliseconds",
+			timestamp:     seconds(0) + "000",
+			opts:          []Option{WithTimestampTolerance(time.Minute, time.Hour)},
+			expectedRules: []RuleID{"timestamp.unit"},
+		},
+		{
+			name:          "bad_prometheus_timestamp_in_seconds",
+			format:        FormatPrometheusText,
+			timestamp:     seconds(0),
+			expectedRules: []RuleID{"timestamp.unit"},
+		},
+		{
+			name:      "good_prometheus_timestamp",
+			format:    FormatPrometheusText,
+			timestamp: seconds(0) + "000",
+		},
+		{
+			name:          "bad_live_target_timestamp",
+			timestamp:     seconds(0),
+			opts:          []Option{WithLiveTarget()},
+			expectedRules: []RuleID{"timestamp.live-target"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]Option{WithClock(clock), WithFormat(tc.format)}, tc.opts...)
+			v := NewValidator(ErrorLevelShould, opts...)
+			export := fmt.Sprintf("# TYPE a gauge\n# HELP a help\na 1 %s\n", tc.timestamp)
+			if tc.format == FormatText {
+				export += "# EOF\n"
+			}
+			err := v.Validate([]byte(export))
+			var ruleIDs []RuleID
+			for _, vi := range v.Report().Violations {
+				ruleIDs = append(ruleIDs, vi.Rule)
+			}
+			require.Equal(t, tc.expectedRules, ruleIDs)
+			if len(tc.expectedRules) == 0 {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateExemplarTimestampTolerance(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	tcs := []struct {
+		name          string
+		opts          []Option
+		future        time.Duration
+		expectedRules []RuleID
+	}{
+		{
+			name:   "good_default_tolerance",
+			future: 30 * time.Second,
+		},
+		{
+			name:          "bad_default_tolerance",
+			future:        2 * time.Minute,
+			expectedRules: []RuleID{"exemplar.timestamp-future"},
+		},
+		{
+			name:          "bad_future_tolerance",
+			opts:          []Option{WithTimestampTolerance(10*time.Second, 0)},
+			future:        30 * time.Second,
+			expectedRules: []RuleID{"exemplar.timestamp-future"},
+		},
+		{
+			name:   "good_future_tolerance",
+			opts:   []Option{WithTimestampTolerance(time.Hour, 0)},
+			future: 2 * time.Minute,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(ErrorLevelShould, append([]Option{WithClock(clock)}, tc.opts...)...)
+			_ = v.Validate([]byte(fmt.Sprintf("# TYPE a counter\na_total 1 # {} 1 %d\n# EOF\n", now.Add(tc.future).Unix())))
+			var ruleIDs []RuleID
+			for _, vi := range v.Report().Violations {
+				ruleIDs = append(ruleIDs, vi.Rule)
+			}
+			require.Equal(t, tc.expectedRules, ruleIDs)
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package validator
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
+	"math/big"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,166 +21,383 @@ import (
 	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/pkg/timestamp"
 	"github.com/prometheus/prometheus/scrape"
-	"go.uber.org/multierr"
 )
 
 var (
 	errExemplar = errorWithLevel{
+		rule:  "exemplar.allowed-types",
 		err:   errors.New("only histogram/gaugehistogram buckets and counters can have exemplars"),
 		level: ErrorLevelMust,
 	}
 
 	errMustNotMixTimestampPresense = errorWithLevel{
+		rule:  "timestamp.mixed-presence",
 		err:   errors.New("Mix of timestamp presence within a group"),
 		level: ErrorLevelMust,
 	}
 
 	errMustLabelNamesBeUnique = errorWithLevel{
+		rule:  "labels.unique-names",
 		err:   errors.New("Label names MUST be unique within a LabelSet"),
 		level: ErrorLevelMust,
 	}
 
 	errMetricTypeAlreadySet = errorWithLevel{
+		rule:  "metadata.type-repeated",
 		err:   errors.New("metric type already set"),
 		level: ErrorLevelMust,
 	}
 
 	errUnitAlreadySet = errorWithLevel{
+		rule:  "metadata.unit-repeated",
 		err:   errors.New("unit already set"),
 		level: ErrorLevelMust,
 	}
 
 	errHelpAlreadySet = errorWithLevel{
+		rule:  "metadata.help-repeated",
 		err:   errors.New("help already set"),
 		level: ErrorLevelMust,
 	}
 
 	errMustTimestampIncrease = errorWithLevel{
+		rule:  "timestamp.monotonic",
 		err:   errors.New("MetricPoints MUST have monotonically increasing timestamps"),
 		level: ErrorLevelMust,
 	}
 
 	errMustNotMetricFamiliesInterleave = errorWithLevel{
+		rule:  "family.interleaved",
 		err:   errors.New("MetricFamilies MUST NOT be interleaved"),
 		level: ErrorLevelMust,
 	}
 
 	errMustNotCounterValueDecrease = errorWithLevel{
+		rule:  "counter.monotonic",
 		err:   errors.New("counter total MUST be monotonically non-decreasing over time"),
 		level: ErrorLevelMust,
 	}
 
+	errMustNotHistogramValueDecrease = errorWithLevel{
+		rule:  "histogram.monotonic",
+		err:   errors.New("histogram bucket, count and sum values are counters so MUST be monotonically non-decreasing over time"),
+		level: ErrorLevelMust,
+	}
+
+	errMustNotSummaryValueDecrease = errorWithLevel{
+		rule:  "summary.monotonic",
+		err:   errors.New("summary count and sum values are counters so MUST be monotonically non-decreasing over time"),
+		level: ErrorLevelMust,
+	}
+
 	errMustCounterValueBeValid = errorWithLevel{
+		rule:  "counter.total-valid",
 		err:   errors.New("A Total is a non-NaN and MUST be monotonically non-decreasing over time, starting from 0"),
 		level: ErrorLevelMust,
 	}
 
 	errCounterValueNaN = errorWithLevel{
+		rule:  "counter.value-nan",
 		err:   errors.New("counter like value must not be NaN"),
 		level: ErrorLevelMust,
 	}
 
 	errCounterValueNegative = errorWithLevel{
+		rule:  "counter.value-negative",
 		err:   errors.New("counter like value must not be negative"),
 		level: ErrorLevelMust,
 	}
 
 	errMustContainPositiveInfBucket = errorWithLevel{
+		rule:  "histogram.inf-bucket",
 		err:   errors.New("Histogram MetricPoints MUST have at least a bucket with an +Inf threshold"),
 		level: ErrorLevelMust,
 	}
 
 	errMustSummaryQuantileBeBetweenZeroAndOne = errorWithLevel{
+		rule:  "summary.quantile-range",
 		err:   errors.New("Quantiles MUST be between 0 and 1 inclusive"),
 		level: ErrorLevelMust,
 	}
 
 	errMustNotSummaryQuantileValueBeNegative = errorWithLevel{
+		rule:  "summary.quantile-value-negative",
 		err:   errors.New("Quantile values MUST NOT be negative"),
 		level: ErrorLevelMust,
 	}
 
 	errInvalidSummaryCountAndSum = errorWithLevel{
+		rule:  "summary.count-sum-valid",
 		err:   errors.New("Count and Sum values are counters so MUST NOT be NaN or negative"),
 		level: ErrorLevelMust,
 	}
 
 	errMustStateSetContainLabel = errorWithLevel{
+		rule:  "stateset.family-label",
 		err:   errors.New("Each State's sample MUST have a label with the MetricFamily name as the label name and the State name as the label value"),
 		level: ErrorLevelMust,
 	}
 
 	errMustNoUnitForStateSet = errorWithLevel{
+		rule:  "stateset.empty-unit",
 		err:   errors.New("MetricFamilies of type StateSets MUST have an empty Unit string"),
 		level: ErrorLevelMust,
 	}
 
+	errMustStateSetHaveOneSamplePerState = errorWithLevel{
+		rule:  "stateset.one-sample-per-state",
+		err:   errors.New("StateSets MUST have one sample per State in the MetricPoint"),
+		level: ErrorLevelMust,
+	}
+
+	errMustEnumStateSetHaveOneTrueState = errorWithLevel{
+		rule:  "stateset.enum-one-true",
+		err:   errors.New("If encoded as a StateSet, ENUMs MUST have exactly one Boolean which is true within a MetricPoint"),
+		level: ErrorLevelMust,
+	}
+
 	errMustNoUnitForInfo = errorWithLevel{
+		rule:  "info.empty-unit",
 		err:   errors.New("MetricFamilies of type Info MUST have an empty Unit string"),
 		level: ErrorLevelMust,
 	}
 
 	errInvalidInfoValue = errorWithLevel{
+		rule:  "info.value",
 		err:   errors.New("The Sample value MUST always be 1"),
 		level: ErrorLevelMust,
 	}
 
 	errInvalidStateSetValue = errorWithLevel{
+		rule:  "stateset.value",
 		err:   errors.New("The State sample's value MUST be 1 if the State is true and MUST be 0 if the State is false"),
 		level: ErrorLevelMust,
 	}
 
 	errMustHistogramBucketsInOrder = errorWithLevel{
+		rule:  "histogram.buckets-ordered",
 		err:   errors.New("histogram must have buckets in order"),
 		level: ErrorLevelMust,
 	}
 
 	errMustHistogramHaveSumAndCount = errorWithLevel{
+		rule:  "histogram.sum-and-count",
 		err:   errors.New("If and only if a Sum Value is present in a MetricPoint, then the MetricPoint's +Inf Bucket value MUST also appear in a Sample with a MetricName with the suffix \"_count\""),
 		level: ErrorLevelMust,
 	}
 
 	errMustHistogramNotHaveSumAndNegative = errorWithLevel{
+		rule:  "histogram.sum-with-negative-buckets",
 		err:   errors.New("Cannot have _sum with negative buckets"),
 		level: ErrorLevelMust,
 	}
 
+	errMustHistogramCountEqualInfBucket = errorWithLevel{
+		rule:  "histogram.count-equals-inf-bucket",
+		err:   errors.New("the MetricPoint's +Inf Bucket value MUST also appear in a Sample with a MetricName with the suffix \"_count\""),
+		level: ErrorLevelMust,
+	}
+
+	errMustHistogramValueBeInteger = errorWithLevel{
+		rule:  "histogram.integer-values",
+		err:   errors.New("Count and bucket values MUST be integers"),
+		level: ErrorLevelMust,
+	}
+
 	errGaugeHistogramBucketValueNaN = errorWithLevel{
+		rule:  "gaugehistogram.bucket-nan",
 		err:   errors.New("gauge histogram bucket value must not be NaN"),
 		level: ErrorLevelMust,
 	}
 
 	errGaugeHistogramBucketValueNegative = errorWithLevel{
+		rule:  "gaugehistogram.bucket-negative",
 		err:   errors.New("gauge histogram bucket value must not be negative"),
 		level: ErrorLevelMust,
 	}
 
 	errGaugeHistogramGSumValueNaN = errorWithLevel{
+		rule:  "gaugehistogram.gsum-nan",
 		err:   errors.New("gauge histogram _gsum value must not be negative"),
 		level: ErrorLevelMust,
 	}
 
 	errMustGaugeHistogramBucketsInOrder = errorWithLevel{
+		rule:  "gaugehistogram.buckets-ordered",
 		err:   errors.New("gauge histogram must have buckets in order"),
 		level: ErrorLevelMust,
 	}
 
+	errMustGaugeHistogramBucketValuesIncrease = errorWithLevel{
+		rule:  "gaugehistogram.buckets-cumulative",
+		err:   errors.New("gauge histogram bucket values must be cumulative"),
+		level: ErrorLevelMust,
+	}
+
+	errMustGaugeHistogramGCountEqualInfBucket = errorWithLevel{
+		rule:  "gaugehistogram.gcount-equals-inf-bucket",
+		err:   errors.New("the MetricPoint's +Inf Bucket value MUST also appear in a Sample with a MetricName with the suffix \"_gcount\""),
+		level: ErrorLevelMust,
+	}
+
+	errMustGaugeHistogramBucketValueBeInteger = errorWithLevel{
+		rule:  "gaugehistogram.integer-values",
+		err:   errors.New("gauge histogram bucket values MUST be integers"),
+		level: ErrorLevelMust,
+	}
+
 	errMustGaugeHistogramNotHaveGSumAndNegative = errorWithLevel{
+		rule:  "gaugehistogram.negative-gsum",
 		err:   errors.New("Cannot have negative _gsum with non-negative buckets"),
 		level: ErrorLevelMust,
 	}
 
 	errMustGaugeHistogramHaveGSumAndGCountOrNeither = errorWithLevel{
+		rule:  "gaugehistogram.gsum-and-gcount",
 		err:   errors.New("must have both _gsum and _gcount or neither"),
 		level: ErrorLevelMust,
 	}
 
+	errMustUnitBeNameSuffix = errorWithLevel{
+		rule:  "unit.name-suffix",
+		err:   errors.New("If non-empty, Unit MUST be a suffix of the MetricFamily name separated by an underscore"),
+		level: ErrorLevelMust,
+	}
+
+	errMustCounterUnitPrecedeTotal = errorWithLevel{
+		rule:  "counter.unit-before-total",
+		err:   errors.New("The Unit of a Counter MUST precede the _total suffix, e.g. foo_seconds_total"),
+		level: ErrorLevelMust,
+	}
+
+	errMustSampleSuffixBeValid = errorWithLevel{
+		rule:  "family.sample-suffix",
+		err:   errors.New("Sample MetricNames MUST have a suffix valid for the type of the MetricFamily"),
+		level: ErrorLevelMust,
+	}
+
+	errMustNotMetricFamilyNamesClash = errorWithLevel{
+		rule:  "family.name-clash",
+		err:   errors.New("The name of a MetricFamily MUST NOT result in a potential clash for sample metric names with another MetricFamily"),
+		level: ErrorLevelMust,
+	}
+
+	errMustNotCreatedDecrease = errorWithLevel{
+		rule:  "created.monotonic",
+		err:   errors.New("Created MUST be set to the timestamp of the last reset so it MUST NOT decrease over time"),
+		level: ErrorLevelMust,
+	}
+
+	errMustProtoNameBeValid = errorWithLevel{
+		rule:  "proto.name-valid",
+		err:   errors.New("MetricFamily and label names MUST be valid"),
+		level: ErrorLevelMust,
+	}
+
+	errMustProtoValueMatchType = errorWithLevel{
+		rule:  "proto.value-type",
+		err:   errors.New("the value of a MetricPoint is required and MUST match the type of the MetricFamily"),
+		level: ErrorLevelMust,
+	}
+
+	errParse = errorWithLevel{
+		rule:  "parse",
+		err:   errors.New("exposition could not be parsed"),
+		level: ErrorLevelMust,
+	}
+
+	errMustMetricHaveName = errorWithLevel{
+		rule:  "labels.metric-name",
+		err:   errors.New("labels must contain metric name"),
+		level: ErrorLevelMust,
+	}
+
+	errMetadataNameChanged = errorWithLevel{
+		rule:  "metadata.name-changed",
+		err:   errors.New("metric name changed within metadata"),
+		level: ErrorLevelMust,
+	}
+
+	errMustSummaryQuantileBeValid = errorWithLevel{
+		rule:  "summary.quantile-label",
+		err:   errors.New("invalid quantile value"),
+		level: ErrorLevelMust,
+	}
+
+	errMustBucketLabelBeValid = errorWithLevel{
+		rule:  "histogram.le-label",
+		err:   errors.New("invalid bucket threshold"),
+		level: ErrorLevelMust,
+	}
+
+	errMustHistogramBucketValuesIncrease = errorWithLevel{
+		rule:  "histogram.buckets-cumulative",
+		err:   errors.New("bucket values must be cumulative"),
+		level: ErrorLevelMust,
+	}
+
+	errMustExemplarBeInBucketRange = errorWithLevel{
+		rule:  "exemplar.bucket-range",
+		err:   errors.New("Each bucket covers the values less and or equal to it, and the value of the exemplar MUST be within this range"),
+		level: ErrorLevelMust,
+	}
+
+	errShouldExemplarBeAbovePreviousBucket = errorWithLevel{
+		rule:  "exemplar.bucket-lower-bound",
+		err:   errors.New("the value of a bucket exemplar SHOULD be greater than the threshold of the previous bucket"),
+		level: ErrorLevelShould,
+	}
+
+	errExemplarLabelsTooLong = errorWithLevel{
+		rule:  "exemplar.labels-length",
+		err:   errors.New("exemplar label contents exceed the maximum length"),
+		level: ErrorLevelMust,
+	}
+
 	errShouldNotMetricsDisappear = errorWithLevel{
+		rule:  "series.disappeared",
 		err:   errors.New("metrics and samples SHOULD NOT appear and disappear from exposition to exposition"),
 		level: ErrorLevelShould,
 	}
 
+	errShouldCreatedChangeOnlyOnReset = errorWithLevel{
+		rule:  "created.changed-without-reset",
+		err:   errors.New("Created SHOULD only change when the MetricPoint is reset"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldNotCreatedBeAfterTimestamp = errorWithLevel{
+		rule:  "created.after-timestamp",
+		err:   errors.New("Created SHOULD NOT be after the timestamp of the MetricPoint"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldNotExemplarExceedCounterIncrease = errorWithLevel{
+		rule:  "exemplar.counter-increase",
+		err:   errors.New("the value of a counter exemplar SHOULD NOT be greater than the increase of the counter it annotates"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldNotExemplarTimestampBeInFuture = errorWithLevel{
+		rule:  "exemplar.timestamp-future",
+		err:   errors.New("exemplar timestamp SHOULD NOT be in the future relative to the scrape"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldNotExemplarTimestampDecrease = errorWithLevel{
+		rule:  "exemplar.timestamp-monotonic",
+		err:   errors.New("exemplar timestamps of a series SHOULD NOT decrease over time"),
+		level: ErrorLevelShould,
+	}
+
+	errShouldStateSetStatesBeStable = errorWithLevel{
+		rule:  "stateset.stable-states",
+		err:   errors.New("the States of a StateSet MetricPoint SHOULD NOT change from exposition to exposition"),
+		level: ErrorLevelShould,
+	}
+
 	errShouldNotDuplicateLabel = errorWithLevel{
+		rule:  "labels.duplicated-on-all-series",
 		err:   errors.New("the same label name and value SHOULD NOT appear on every Metric within a MetricSet"),
 		level: ErrorLevelShould,
 	}
@@ -197,6 +419,28 @@ type validSuffixes struct {
 	allowEmpty bool
 }
 
+// _allSuffixes is the list of all the reserved suffixes, ordered so that the
+// longest suffix is matched first.
+var _allSuffixes = func() []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, vs := range _reservedSuffixes {
+		for _, suffix := range vs.suffixes {
+			if !seen[suffix] {
+				seen[suffix] = true
+				res = append(res, suffix)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if len(res[i]) != len(res[j]) {
+			return len(res[i]) > len(res[j])
+		}
+		return res[i] < res[j]
+	})
+	return res
+}()
+
 // ErrorLevel is the level of the validation error.
 // The OpenMetrics spec defines rules in different categories like "SHOULD"
 // and "MUST", the value of ErrorLevel identifies which category is the error
@@ -205,15 +449,22 @@ type ErrorLevel int
 
 // A list of supported error levels, ordered by severity.
 const (
-	ErrorLevelShould ErrorLevel = iota
-	ErrorLevelMust
+	// ErrorLevelLint is the level of the best practices which are not part
+	// of the spec, e.g. the Prometheus naming conventions. It is below the
+	// levels of the spec, whose values are kept.
+	ErrorLevelLint   ErrorLevel = -1
+	ErrorLevelShould ErrorLevel = 0
+	ErrorLevelMust   ErrorLevel = 1
 )
 
-var validErrorLevels = []ErrorLevel{ErrorLevelShould, ErrorLevelMust}
+var validErrorLevels = []ErrorLevel{ErrorLevelLint, ErrorLevelShould, ErrorLevelMust}
 
 // String returns a readable value for the error level.
 // Use custom string value here because the standard string `ErrorLevelMust` is not ergnonomic in tooling.
 func (el ErrorLevel) String() string {
+	if el == ErrorLevelLint {
+		return "lint"
+	}
 	if el == ErrorLevelShould {
 		return "should"
 	}
@@ -233,7 +484,11 @@ func NewErrorLevel(str string) (ErrorLevel, error) {
 	return 0, fmt.Errorf("unknown error level %q", str)
 }
 
+// RuleID is the stable identifier of a validation rule, e.g. "counter.monotonic".
+type RuleID string
+
 type errorWithLevel struct {
+	rule  RuleID
 	err   error
 	level ErrorLevel
 }
@@ -242,19 +497,39 @@ func (e errorWithLevel) Error() string {
 	return e.err.Error()
 }
 
-// tryReport reports the error if the level is equal or above the target level, otherwise the error is omitted.
-func (e errorWithLevel) tryReport(level ErrorLevel) error {
-	if e.level >= level {
-		return e
-	}
-	return nil
+func (e errorWithLevel) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the target is an error of the same rule, so that errors
+// created with withMessage still match their sentinel.
+func (e errorWithLevel) Is(target error) bool {
+	t, ok := target.(errorWithLevel)
+	return ok && t.rule == e.rule
+}
+
+// withMessage returns a copy of the error with a more specific message.
+func (e errorWithLevel) withMessage(format string, args ...interface{}) errorWithLevel {
+	e.err = fmt.Errorf(format, args...)
+	return e
 }
 
 type metric struct {
+	mfn string
+	// point identifies the MetricPoint of the sample within the metric family,
+	// e.g. all the buckets of a histogram share the same point.
+	point string
+	// pos is the position of the sample, and columns the offsets of its
+	// fields on the line.
+	pos       Position
+	columns   sampleColumns
 	lset      labels.Labels
 	timestamp int64
 	value     float64
-	exemplar  *exemplar.Exemplar
+	// exactValue is the integer value of the sample if float64 cannot
+	// represent it exactly, nil otherwise.
+	exactValue *big.Int
+	exemplar   *exemplar.Exemplar
 }
 
 type histogramMetric struct {
@@ -275,11 +550,21 @@ func (m metric) String() string {
 }
 
 type metricFamily struct {
-	metricType          *textparse.MetricType
-	help                *string
-	unit                *string
-	metrics             map[string]metric
+	// pos is the position of the first entry of the metric family.
+	pos        Position
+	metricType *textparse.MetricType
+	help       *string
+	unit       *string
+	// metrics are the last samples of each series, they are kept for the
+	// comparison with the next metric set.
+	metrics map[string]metric
+	// orderedByAppearance are the samples of the metric family, they are
+	// released once the metric family is validated.
 	orderedByAppearance []metric
+	// created are the samples with the created timestamp, keyed by point.
+	created map[string]metric
+	// validated is set once the metric family is complete and validated.
+	validated bool
 
 	metricWithoutTimestampRecorded bool
 	metricWithTimestampRecorded    bool
@@ -288,6 +573,7 @@ type metricFamily struct {
 func newMetricFamily() *metricFamily {
 	return &metricFamily{
 		metrics: make(map[string]metric),
+		created: make(map[string]metric),
 	}
 }
 
@@ -314,6 +600,31 @@ func (mf *metricFamily) resetAfterValidate() {
 	mf.orderedByAppearance = mf.orderedByAppearance[:0]
 }
 
+// metricPoints returns the samples of the metric family grouped by
+// MetricPoint, in order of appearance. Samples of the same series with
+// different timestamps belong to different MetricPoints.
+func (mf *metricFamily) metricPoints() [][]metric {
+	type pointKey struct {
+		point     string
+		timestamp int64
+	}
+	var (
+		points  [][]metric
+		indexes = make(map[pointKey]int)
+	)
+	for _, m := range mf.orderedByAppearance {
+		key := pointKey{point: m.point, timestamp: m.timestamp}
+		i, ok := indexes[key]
+		if !ok {
+			i = len(points)
+			indexes[key] = i
+			points = append(points, nil)
+		}
+		points[i] = append(points[i], m)
+	}
+	return points
+}
+
 func (mf *metricFamily) MetricType() textparse.MetricType {
 	if mf.metricType == nil {
 		return textparse.MetricTypeUnknown
@@ -323,7 +634,18 @@ func (mf *metricFamily) MetricType() textparse.MetricType {
 
 type nowFn func() time.Time
 
+// _exemplarFutureTolerance is how far exemplar timestamps may be in the future
+// relative to the scrape before they are reported, unless the future tolerance
+// of WithTimestampTolerance is set.
+const _exemplarFutureTolerance = time.Minute
+
+// _maxChunkSize is the size above which ValidateReader parses the exposition
+// read so far even if the metric family is not complete, the samples of the
+// metric family are kept until it is complete.
+const _maxChunkSize = 1 << 20
+
 // OpenMetricsValidator validates metrics against OpenMetrics spec.
+// It is not safe for concurrent use, see MultiTargetValidator.
 type OpenMetricsValidator struct {
 	level                ErrorLevel
 	lastMetricSet        map[string]*metricFamily
@@ -331,60 +653,314 @@ type OpenMetricsValidator struct {
 	lastMetricFamilyName string
 	seenLabelSets        map[uint64]labels.Labels
 	lastLabelSet         labels.Labels
-	mErr                 error
+	report               Report
+	rules                Rules
+	// enumStateSets are the names of the StateSet metric families which
+	// encode an ENUM.
+	enumStateSets map[string]bool
+	// pos is the position of the entry being validated, and columns the
+	// offsets of its fields if it is a sample.
+	pos     Position
+	columns sampleColumns
+	// scrapeTime is the time of the exposition being validated in milliseconds.
+	scrapeTime int64
+	// metadata and dataPointFound track the metadata of the metric family
+	// being read, they are kept between the chunks of an exposition.
+	metadata       scrape.MetricMetadata
+	dataPointFound bool
+	// format is the format of the expositions.
+	format Format
+	// pendingHelp is the HELP entry waiting for its metric family to be known
+	// in the Prometheus text format.
+	pendingHelp *pendingHelp
+	// maxErrors and maxSeries are the limits per exposition, zero means no
+	// limit. scrapeSeries counts towards maxSeries.
+	maxErrors    int
+	maxSeries    int
+	scrapeSeries int
+	// maxTimestampFuture and maxTimestampPast are how far sample timestamps
+	// may be from the time of the scrape, zero disables the checks.
+	maxTimestampFuture time.Duration
+	maxTimestampPast   time.Duration
+	// liveTarget is set when the expositions are scraped from a live target.
+	liveTarget bool
 
 	nowFn nowFn
 }
 
+// Option sets options in OpenMetricsValidator.
+type Option func(*OpenMetricsValidator)
+
+// WithRules sets the rules configuration, by default all the rules are
+// enabled at their default level.
+func WithRules(rules Rules) Option {
+	return func(v *OpenMetricsValidator) {
+		v.rules = rules
+	}
+}
+
+// WithFormat sets the format of the expositions, by default the OpenMetrics
+// text format.
+func WithFormat(f Format) Option {
+	return func(v *OpenMetricsValidator) {
+		v.format = f
+	}
+}
+
+// WithClock sets the clock the time of the scrapes is read from, by default
+// time.Now. Timestamps are checked relative to the time of the scrape, so
+// captured expositions can be replayed with their original capture time.
+func WithClock(now func() time.Time) Option {
+	return func(v *OpenMetricsValidator) {
+		v.nowFn = now
+	}
+}
+
+// WithMaxErrors sets the maximum number of violations reported per
+// exposition, the following ones are omitted and the report is marked as
+// truncated. By default there is no limit.
+func WithMaxErrors(n int) Option {
+	return func(v *OpenMetricsValidator) {
+		v.maxErrors = n
+	}
+}
+
+// WithMaxSeries sets the maximum number of series validated per exposition,
+// the samples of the following series are ignored and the report is marked as
+// truncated. By default there is no limit.
+func WithMaxSeries(n int) Option {
+	return func(v *OpenMetricsValidator) {
+		v.maxSeries = n
+	}
+}
+
+// WithTimestampTolerance sets how far in the future and in the past sample
+// timestamps may be relative to the time of the scrape, zero disables the
+// check. By default both checks are disabled. The future tolerance applies to
+// exemplar timestamps too, which may be up to a minute in the future by
+// default.
+func WithTimestampTolerance(future, past time.Duration) Option {
+	return func(v *OpenMetricsValidator) {
+		v.maxTimestampFuture = future
+		v.maxTimestampPast = past
+	}
+}
+
+// WithLiveTarget sets that the expositions are scraped from a live target,
+// whose samples should not have timestamps.
+func WithLiveTarget() Option {
+	return func(v *OpenMetricsValidator) {
+		v.liveTarget = true
+	}
+}
+
+// WithEnumStateSets sets the names of the StateSet metric families which encode
+// an ENUM, so exactly one of their States must be true within a MetricPoint.
+func WithEnumStateSets(mfns ...string) Option {
+	return func(v *OpenMetricsValidator) {
+		if v.enumStateSets == nil {
+			v.enumStateSets = make(map[string]bool, len(mfns))
+		}
+		for _, mfn := range mfns {
+			v.enumStateSets[mfn] = true
+		}
+	}
+}
+
 // NewValidator creates an OpenMetricsValidator.
-func NewValidator(level ErrorLevel) *OpenMetricsValidator {
-	return &OpenMetricsValidator{
+func NewValidator(level ErrorLevel, opts ...Option) *OpenMetricsValidator {
+	v := &OpenMetricsValidator{
 		lastMetricSet: make(map[string]*metricFamily),
 		curMetricSet:  make(map[string]*metricFamily),
 		seenLabelSets: make(map[uint64]labels.Labels),
 		level:         level,
 		nowFn:         time.Now,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
 // Reset resets the validator.
 func (v *OpenMetricsValidator) Reset() {
 	v.lastMetricSet = make(map[string]*metricFamily)
 	v.curMetricSet = make(map[string]*metricFamily)
-	v.mErr = nil
+	v.report = Report{}
+}
+
+// Report returns the violations found in the last validated exposition.
+func (v *OpenMetricsValidator) Report() Report {
+	report := v.report
+	report.Violations = append([]Violation(nil), v.report.Violations...)
+	return report
 }
 
 // Validate parses the bytes and validates the metrics against OpenMetrics spec.
+// The returned error only holds the violations of this exposition.
 func (v *OpenMetricsValidator) Validate(b []byte) error {
+	if v.format == FormatProtobuf {
+		return v.ValidateProto(b)
+	}
+	v.startScrape()
+	if !v.validate(b, Position{Line: 1, Column: 1}, true) {
+		v.abortScrape()
+	}
+	return v.report.Err()
+}
+
+// ValidateReader reads the exposition from r and validates the metrics against
+// OpenMetrics spec. Unlike Validate, the exposition is parsed in chunks of
+// whole metric families and each metric family is validated once complete, so
+// neither the raw exposition nor all its samples are kept in memory, only the
+// last sample of each series the next exposition is compared with. The
+// exposition is discarded if it cannot be parsed or the context is done before
+// it is fully read.
+//
+// The memory is bounded per metric family rather than per exposition: the
+// raw text of a large metric family is parsed in chunks too, but all its
+// samples are kept until it is complete since its rules span them, e.g. the
+// buckets of a histogram.
+func (v *OpenMetricsValidator) ValidateReader(ctx context.Context, r io.Reader) error {
+	if v.format == FormatProtobuf {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return v.ValidateProto(b)
+	}
+	v.startScrape()
+	var (
+		br            = bufio.NewReader(r)
+		base          = Position{Line: 1, Column: 1}
+		chunk         []byte
+		sampleInChunk bool
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			v.abortScrape()
+			return err
+		}
+		line, err := br.ReadBytes('\n')
+		if v.format == FormatText && isEOFLine(line) {
+			// Keep what follows "# EOF" so the parser reports it.
+			rest, _ := ioutil.ReadAll(io.LimitReader(br, _maxChunkSize))
+			chunk = append(append(chunk, line...), rest...)
+			if !v.validate(chunk, base, true) {
+				v.abortScrape()
+			}
+			return v.report.Err()
+		}
+		// Validate the chunk when a new metric family starts, or when it grows
+		// too large because of a metric family with many samples.
+		if len(line) > 0 && ((sampleInChunk && isMetadataLine(line)) || len(chunk) >= _maxChunkSize) {
+			if !v.validate(chunk, base, false) {
+				v.abortScrape()
+				return v.report.Err()
+			}
+			base = base.advance(chunk)
+			chunk = chunk[:0]
+			sampleInChunk = false
+		}
+		chunk = append(chunk, line...)
+		if len(line) > 0 && line[0] != '#' {
+			sampleInChunk = true
+		}
+		if err == io.EOF {
+			// The exposition does not end with "# EOF", let the parser report it
+			// unless the format has no such marker.
+			if !v.validate(chunk, base, true) {
+				v.abortScrape()
+			}
+			return v.report.Err()
+		}
+		if err != nil {
+			v.abortScrape()
+			return err
+		}
+	}
+}
+
+// startScrape prepares the validator for a new exposition, the violations of
+// the previous one are discarded.
+func (v *OpenMetricsValidator) startScrape() {
+	v.report = Report{}
+	v.scrapeTime = timestamp.FromTime(v.nowFn())
+	v.metadata = scrape.MetricMetadata{}
+	v.dataPointFound = false
+	v.pendingHelp = nil
+	v.scrapeSeries = 0
+}
+
+// abortScrape discards the metrics recorded for an exposition which could not
+// be fully read.
+func (v *OpenMetricsValidator) abortScrape() {
+	v.curMetricSet = make(map[string]*metricFamily, len(v.lastMetricSet))
+	v.lastMetricFamilyName = ""
+	v.lastLabelSet = nil
+	v.seenLabelSets = make(map[uint64]labels.Labels)
+}
+
+// validate parses the entries of b, whose first entry is at base, and
+// validates the metrics. Unless final is set, b is a chunk of the exposition
+// made of whole lines and the metric set is only validated by the final chunk.
+// It returns false if the entries could not be parsed.
+func (v *OpenMetricsValidator) validate(b []byte, base Position, final bool) bool {
+	prometheusText := v.format == FormatPrometheusText
+	if !final && !prometheusText {
+		b = append(b, "# EOF\n"...)
+	}
 	var (
-		p              = textparse.NewOpenMetricsParser(b)
-		defTime        = timestamp.FromTime(v.nowFn())
-		m              scrape.MetricMetadata
-		dataPointFound bool
+		p     textparse.Parser
+		lines = newLineTracker(b, base)
 	)
+	if prometheusText {
+		p = textparse.NewPromParser(b)
+		lines.skipBlankLines = true
+	} else {
+		p = textparse.NewOpenMetricsParser(b)
+	}
 	for {
 		// TODO: Handle exemplar.
+		v.pos = lines.next()
 		et, err := p.Next()
+		if prometheusText && et != textparse.EntryType && et != textparse.EntryHelp {
+			v.flushPrometheusHelp()
+		}
 		if err == io.EOF {
-			// Validate at the end of a scrape.
-			v.validateRecorded()
-			return v.mErr
+			if final {
+				// Validate at the end of a scrape.
+				v.validateRecorded()
+			}
+			return true
 		}
 		if err != nil {
-			return multierr.Append(v.mErr, err)
+			v.addViolation(Violation{Pos: v.pos.at(parseErrorColumn(lines.entry, err))}, errParse.withMessage("%v", err))
+			return false
 		}
 		switch et {
 		case textparse.EntryType:
 			mfn, metricType := p.Type()
-			v.recordMetricType(string(mfn), metricType, &dataPointFound, &m)
+			if prometheusText {
+				v.recordPrometheusType(string(mfn), metricType)
+				continue
+			}
+			v.recordMetricType(string(mfn), metricType, &v.dataPointFound, &v.metadata)
 			continue
 		case textparse.EntryHelp:
 			mfn, helpBytes := p.Help()
-			v.recordHelp(string(mfn), string(helpBytes), &dataPointFound, &m)
+			if prometheusText {
+				v.recordPrometheusHelp(string(mfn), string(helpBytes))
+				continue
+			}
+			v.recordHelp(string(mfn), string(helpBytes), &v.dataPointFound, &v.metadata)
 			continue
 		case textparse.EntryUnit:
 			mfn, unitBytes := p.Unit()
-			v.recordUnit(string(mfn), string(unitBytes), &dataPointFound, &m)
+			v.recordUnit(string(mfn), string(unitBytes), &v.dataPointFound, &v.metadata)
 			continue
 		case textparse.EntryComment:
 			continue
@@ -392,10 +968,10 @@ func (v *OpenMetricsValidator) Validate(b []byte) error {
 		}
 
 		var (
-			t             = defTime
+			t             = v.scrapeTime
 			withTimestamp bool
 		)
-		_, tp, value := p.Series()
+		series, tp, value := p.Series()
 		if tp != nil {
 			withTimestamp = true
 			t = *tp
@@ -409,7 +985,8 @@ func (v *OpenMetricsValidator) Validate(b []byte) error {
 
 		mn := lset.Get(labels.MetricName)
 		if mn == "" {
-			v.mErr = multierr.Append(v.mErr, fmt.Errorf("labels must contain metric name %q", lset.String()))
+			v.addViolation(Violation{Pos: v.pos, Labels: lset},
+				errMustMetricHaveName.withMessage("labels must contain metric name %q", lset.String()))
 			continue
 		}
 
@@ -418,13 +995,27 @@ func (v *OpenMetricsValidator) Validate(b []byte) error {
 			maybeExemplar = &e
 		}
 
-		v.recordMetric(mn, lset, t, value, maybeExemplar, withTimestamp)
+		exactValue := parseInexactInt(sampleValue(lines.entry, series))
+		v.columns = newSampleColumns(lines.entry)
+		v.recordMetric(mn, lset, t, value, exactValue, maybeExemplar, withTimestamp)
 
 		// Mark that a metric data point is found.
-		dataPointFound = true
+		v.dataPointFound = true
 	}
 }
 
+// isEOFLine returns whether the line is the "# EOF" marker.
+func isEOFLine(line []byte) bool {
+	return string(bytes.TrimSuffix(line, []byte("\n"))) == "# EOF"
+}
+
+// isMetadataLine returns whether the line holds the metadata of a metric family.
+func isMetadataLine(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("# TYPE ")) ||
+		bytes.HasPrefix(line, []byte("# HELP ")) ||
+		bytes.HasPrefix(line, []byte("# UNIT "))
+}
+
 // tryResetMetadata resets the metadata if the parser finds metadata
 // for a new metric.
 func (v *OpenMetricsValidator) tryResetMetadata(dataPointFound *bool, m *scrape.MetricMetadata, mfn string) {
@@ -436,17 +1027,23 @@ func (v *OpenMetricsValidator) tryResetMetadata(dataPointFound *bool, m *scrape.
 		return
 	}
 	if m.Metric != "" && m.Metric != mfn {
-		v.addMetricFamilyError(m.Metric,
-			fmt.Errorf("metric name changed from %q to %q", m.Metric, mfn))
+		v.addMetricFamilyError(m.Metric, v.pos,
+			errMetadataNameChanged.withMessage("metric name changed from %q to %q", m.Metric, mfn))
 		return
 	}
 	m.Metric = mfn
 }
 
 func (v *OpenMetricsValidator) addOrGetMetricFamily(mfn string) *metricFamily {
+	if v.lastMetricFamilyName != "" && mfn != v.lastMetricFamilyName {
+		// The last metric family is complete unless the metric families are
+		// interleaved, validate it to release its samples.
+		v.finishMetricFamily(v.lastMetricFamilyName)
+	}
 	mf, ok := v.curMetricSet[mfn]
 	if !ok {
 		mf = newMetricFamily()
+		mf.pos = v.pos
 		v.curMetricSet[mfn] = mf
 		v.lastMetricFamilyName = mfn
 		v.lastLabelSet = nil
@@ -458,7 +1055,7 @@ func (v *OpenMetricsValidator) addOrGetMetricFamily(mfn string) *metricFamily {
 		// When the metric family name differs from the last seen metric family name
 		// and the metric family is already created, it means the metric families
 		// are interleaved.
-		v.addMetricFamilyError(mfn, errMustNotMetricFamiliesInterleave)
+		v.addMetricFamilyError(mfn, v.pos, errMustNotMetricFamiliesInterleave)
 	}
 	v.lastMetricFamilyName = mfn
 	return mf
@@ -472,7 +1069,7 @@ func (v *OpenMetricsValidator) recordMetricType(
 ) {
 	mf := v.addOrGetMetricFamily(mfn)
 	if mf.metricType != nil {
-		v.addMetricFamilyError(mfn, errMetricTypeAlreadySet)
+		v.addMetricFamilyError(mfn, v.pos, errMetricTypeAlreadySet)
 		return
 	}
 	mf.metricType = &mt
@@ -488,7 +1085,7 @@ func (v *OpenMetricsValidator) recordHelp(
 ) {
 	mf := v.addOrGetMetricFamily(mfn)
 	if mf.help != nil {
-		v.addMetricFamilyError(mfn, errHelpAlreadySet)
+		v.addMetricFamilyError(mfn, v.pos, errHelpAlreadySet)
 		return
 	}
 	mf.help = &help
@@ -504,7 +1101,7 @@ func (v *OpenMetricsValidator) recordUnit(
 ) {
 	mf := v.addOrGetMetricFamily(mfn)
 	if mf.unit != nil {
-		v.addMetricFamilyError(mfn, errUnitAlreadySet)
+		v.addMetricFamilyError(mfn, v.pos, errUnitAlreadySet)
 		return
 	}
 	mf.unit = &unit
@@ -517,10 +1114,14 @@ func (v *OpenMetricsValidator) recordMetric(
 	lset labels.Labels,
 	timestamp int64,
 	value float64,
+	exactValue *big.Int,
 	e *exemplar.Exemplar,
 	withTimestamp bool,
 ) {
-	mfn := v.sanitizedMetricName(mn)
+	mfn, validSuffix := v.resolveMetricFamily(mn)
+	if !v.allowSeries(mfn, lset) {
+		return
+	}
 	mf := v.addOrGetMetricFamily(mfn)
 	mf.trySetDefaultMetadata()
 	if withTimestamp {
@@ -528,16 +1129,33 @@ func (v *OpenMetricsValidator) recordMetric(
 	} else {
 		mf.metricWithoutTimestampRecorded = true
 	}
+	ignoredLabels := getIgnoredLabels(mn, mfn, mf)
 	cur := metric{
-		lset:      lset,
-		value:     value,
-		timestamp: timestamp,
-		exemplar:  e,
+		mfn:        mfn,
+		point:      labelKey(lset.WithoutLabels(ignoredLabels...)),
+		pos:        v.pos,
+		columns:    v.columns,
+		lset:       lset,
+		value:      value,
+		exactValue: exactValue,
+		timestamp:  timestamp,
+		exemplar:   e,
 	}
 	mf.orderedByAppearance = append(mf.orderedByAppearance, cur)
+	if !validSuffix {
+		v.addMetricError(cur, errMustSampleSuffixBeValid.withMessage(
+			"sample name %q is not valid for metric family %q of type %s", mn, mfn, mf.MetricType()))
+	}
 	v.validateMetric(mn, mf.MetricType(), cur)
+	v.validateValuePrecision(cur)
+	if withTimestamp {
+		v.validateTimestamp(cur)
+	}
+	if isCreatedSample(mn, mfn, mf.MetricType()) {
+		mf.created[cur.point] = cur
+		v.validateCreated(cur)
+	}
 
-	ignoredLabels := getIgnoredLabels(mn, mfn, mf)
 	hash, _ := lset.HashWithoutLabels([]byte{}, ignoredLabels...)
 	_, seen := v.seenLabelSets[hash]
 	if v.lastLabelSet != nil && !labels.Equal(v.lastLabelSet, lset.WithoutLabels(ignoredLabels...)) && seen {
@@ -553,27 +1171,41 @@ func (v *OpenMetricsValidator) recordMetric(
 		mf.metrics[key] = cur
 		return
 	}
-	v.compareMetric(mn, mf.MetricType(), last, cur)
+	v.compareMetric(mn, mf.MetricType(), last, cur, false)
+}
+
+// finishMetricFamily validates the complete metric family and releases its
+// samples, only the last sample of each series is kept. A metric family
+// interleaved with another one, which is a violation already, is validated
+// as a whole once.
+func (v *OpenMetricsValidator) finishMetricFamily(mfn string) {
+	mf, ok := v.curMetricSet[mfn]
+	if !ok {
+		return
+	}
+	if !mf.validated {
+		mf.validated = true
+		v.validateMetricFamily(mfn, mf)
+		v.lintMetricFamily(mfn, mf)
+	}
+	mf.resetAfterValidate()
 }
 
 func (v *OpenMetricsValidator) validateRecorded() {
+	v.finishMetricFamily(v.lastMetricFamilyName)
 	v.validateLabels()
-	for mfn, curMF := range v.curMetricSet {
-		v.validateMetricFamily(mfn, curMF)
-	}
+	v.validateNameClashes()
 	for mfn, lastMF := range v.lastMetricSet {
 		curMF, ok := v.curMetricSet[mfn]
 		if ok {
 			v.compareMetricFamilies(mfn, lastMF, curMF)
 			continue
 		}
-		v.addMetricFamilyError(mfn, errShouldNotMetricsDisappear.tryReport(v.level))
-	}
-	for _, mf := range v.curMetricSet {
-		mf.resetAfterValidate()
+		v.reportChangedSeries(mfn, Position{}, lastMF, nil)
 	}
 	v.lastMetricSet = v.curMetricSet
 	v.curMetricSet = make(map[string]*metricFamily, len(v.lastMetricSet))
+	v.lastMetricFamilyName = ""
 }
 
 // validateLabels makes sure that the same label name and value does not appear
@@ -603,7 +1235,7 @@ func (v *OpenMetricsValidator) validateLabels() {
 		return
 	}
 	if len(lset) > 0 {
-		v.mErr = multierr.Append(v.mErr, errShouldNotDuplicateLabel.tryReport(v.level))
+		v.addViolation(Violation{}, errShouldNotDuplicateLabel)
 	}
 }
 
@@ -619,14 +1251,186 @@ func duplicatedLabels(this, other labels.Labels) labels.Labels {
 }
 
 func (v *OpenMetricsValidator) compareMetricFamilies(mfn string, last, cur *metricFamily) {
+	if cur.MetricType() == textparse.MetricTypeStateset {
+		v.compareStateSets(mfn, last, cur)
+	}
+	resets := v.compareCreated(last, cur)
+	decreased := make(map[string]bool)
 	for lset, lastMF := range last.metrics {
 		curMF, ok := cur.metrics[lset]
-		if ok {
-			v.compareMetric(mfn, cur.MetricType(), lastMF, curMF)
+		if !ok {
+			continue
+		}
+		if v.compareMetric(mfn, cur.MetricType(), lastMF, curMF, resets[curMF.point] != nil) {
+			decreased[curMF.point] = true
+		}
+	}
+	v.reportChangedSeries(mfn, cur.pos, last, cur)
+
+	points := make([]string, 0, len(resets))
+	for point := range resets {
+		points = append(points, point)
+	}
+	sort.Strings(points)
+	for _, point := range points {
+		// A reset is expected to restart the counters of the MetricPoint. The
+		// counters of a MetricPoint created after the last exposition may have
+		// grown past their last values though, so the created timestamp only
+		// changed without a reset if it is not after the last exposition.
+		created := resets[point]
+		if !decreased[point] && created.value*1000 <= float64(last.created[point].timestamp) {
+			v.addMetricErrorAt(*created, created.columns.value, errShouldCreatedChangeOnlyOnReset)
+		}
+	}
+}
+
+// _maxListedSeries is the maximum number of series listed in the message of a
+// violation, all of them are listed in its fields.
+const _maxListedSeries = 10
+
+// reportChangedSeries reports the series of the last metric family which
+// disappeared from the current one, which is nil if the whole metric family
+// disappeared, and the series which newly appeared in the current one. A
+// single violation lists both for the metric family, to tell label churn from
+// lost or new series. A metric family which newly appears as a whole is not
+// reported, since a target may only expose it once it has something to expose.
+func (v *OpenMetricsValidator) reportChangedSeries(mfn string, pos Position, last, cur *metricFamily) {
+	disappeared := missingSeries(mfn, last, cur)
+	appeared := missingSeries(mfn, cur, last)
+	if len(disappeared) == 0 && len(appeared) == 0 {
+		return
+	}
+	err := errShouldNotMetricsDisappear.withMessage("%v: %d series disappeared%s, %d series appeared%s",
+		errShouldNotMetricsDisappear.err, len(disappeared), listSeries(disappeared), len(appeared), listSeries(appeared))
+	v.addViolation(Violation{
+		Pos:          pos,
+		MetricFamily: mfn,
+		Disappeared:  disappeared,
+		Appeared:     appeared,
+	}, err)
+}
+
+// missingSeries returns the sorted label sets of the series of a which are
+// not in b. The samples of a MetricPoint, e.g. the buckets, count and sum of a
+// histogram, are a single series named after the metric family.
+func missingSeries(mfn string, a, b *metricFamily) []labels.Labels {
+	if a == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	if b != nil {
+		for _, m := range b.metrics {
+			seen[m.point] = true
+		}
+	}
+	var res []labels.Labels
+	for _, m := range a.metrics {
+		if seen[m.point] {
+			continue
+		}
+		seen[m.point] = true
+		lset := m.lset.WithoutLabels(getIgnoredLabels(m.lset.Get(labels.MetricName), mfn, a)...)
+		res = append(res, labels.NewBuilder(lset).Set(labels.MetricName, mfn).Labels())
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return labels.Compare(res[i], res[j]) < 0
+	})
+	return res
+}
+
+// listSeries formats the first series of the list, e.g. ` (a_total{b="1"})`.
+func listSeries(series []labels.Labels) string {
+	if len(series) == 0 {
+		return ""
+	}
+	var names []string
+	for i, lset := range series {
+		if i == _maxListedSeries {
+			names = append(names, fmt.Sprintf("and %d more", len(series)-i))
+			break
+		}
+		name := lset.Get(labels.MetricName)
+		if rest := lset.WithoutLabels(labels.MetricName); len(rest) > 0 {
+			name += rest.String()
+		}
+		names = append(names, name)
+	}
+	return " (" + strings.Join(names, ", ") + ")"
+}
+
+// compareStateSets makes sure that the States of each StateSet MetricPoint are
+// the same as in the last metric set.
+func (v *OpenMetricsValidator) compareStateSets(mfn string, last, cur *metricFamily) {
+	lastStates := stateSetStates(mfn, last)
+	curStates := stateSetStates(mfn, cur)
+	points := make([]string, 0, len(curStates))
+	for point := range curStates {
+		points = append(points, point)
+	}
+	sort.Strings(points)
+	for _, point := range points {
+		lastMetrics, ok := lastStates[point]
+		if !ok {
+			continue
+		}
+		curMetrics := curStates[point]
+		lastNames, curNames := stateNames(mfn, lastMetrics), stateNames(mfn, curMetrics)
+		if strings.Join(lastNames, ",") != strings.Join(curNames, ",") {
+			v.addMetricError(curMetrics[0], errShouldStateSetStatesBeStable.withMessage(
+				"states changed from %q to %q", lastNames, curNames))
+		}
+	}
+}
+
+// stateSetStates returns the samples of each StateSet MetricPoint keyed by
+// point, ordered by State.
+func stateSetStates(mfn string, mf *metricFamily) map[string][]metric {
+	states := make(map[string][]metric)
+	for _, m := range mf.metrics {
+		states[m.point] = append(states[m.point], m)
+	}
+	for _, ms := range states {
+		sort.Slice(ms, func(i, j int) bool {
+			return ms[i].lset.Get(mfn) < ms[j].lset.Get(mfn)
+		})
+	}
+	return states
+}
+
+// stateNames returns the names of the States of the samples.
+func stateNames(mfn string, ms []metric) []string {
+	names := make([]string, 0, len(ms))
+	for _, m := range ms {
+		names = append(names, m.lset.Get(mfn))
+	}
+	return names
+}
+
+// compareCreated compares the created timestamps of the MetricPoints against
+// the last metric set and returns the created samples of the MetricPoints
+// which were reset, keyed by point.
+func (v *OpenMetricsValidator) compareCreated(last, cur *metricFamily) map[string]*metric {
+	resets := make(map[string]*metric)
+	for point, curCreated := range cur.created {
+		lastCreated, ok := last.created[point]
+		if !ok {
 			continue
 		}
-		v.addMetricFamilyError(mfn, errShouldNotMetricsDisappear.tryReport(v.level))
+		switch {
+		case curCreated.value < lastCreated.value:
+			v.addMetricErrorAt(curCreated, curCreated.columns.value, errMustNotCreatedDecrease)
+		case curCreated.value > lastCreated.value:
+			created := curCreated
+			resets[point] = &created
+		}
 	}
+	return resets
+}
+
+// isCreatedSample returns whether the sample holds the created timestamp of a
+// MetricPoint.
+func isCreatedSample(mn, mfn string, mt textparse.MetricType) bool {
+	return mn == mfn+"_created" && isValidSuffix(mt, "_created")
 }
 
 func getIgnoredLabels(name string, mfn string, cur *metricFamily) []string {
@@ -643,6 +1447,8 @@ func getIgnoredLabels(name string, mfn string, cur *metricFamily) []string {
 		if name == mfn {
 			ignored = append(ignored, "quantile")
 		}
+	case textparse.MetricTypeStateset:
+		ignored = append(ignored, mfn)
 	}
 
 	return ignored
@@ -651,11 +1457,12 @@ func getIgnoredLabels(name string, mfn string, cur *metricFamily) []string {
 func (v *OpenMetricsValidator) validateMetricFamily(mfn string, cur *metricFamily) {
 	cur.trySetDefaultMetadata()
 	if cur.metricWithTimestampRecorded && cur.metricWithoutTimestampRecorded {
-		v.addMetricFamilyError(mfn, errMustNotMixTimestampPresense)
+		v.addMetricFamilyError(mfn, cur.pos, errMustNotMixTimestampPresense)
 	}
+	v.validateMetricFamilyTimestamps(mfn, cur)
 	switch cur.MetricType() {
 	case textparse.MetricTypeCounter:
-		v.validateMetricFamilyCounter(cur)
+		v.validateMetricFamilyCounter(mfn, cur)
 	case textparse.MetricTypeGaugeHistogram:
 		v.validateMetricFamilyGaugeHistogram(mfn, cur)
 	case textparse.MetricTypeHistogram:
@@ -669,12 +1476,19 @@ func (v *OpenMetricsValidator) validateMetricFamily(mfn string, cur *metricFamil
 	}
 }
 
-func (v *OpenMetricsValidator) validateMetricFamilyCounter(cur *metricFamily) {
+func (v *OpenMetricsValidator) validateMetricFamilyCounter(mfn string, cur *metricFamily) {
+	// The _total suffix is appended after the unit, so a counter family with a
+	// unit must not have the _total suffix in front of the unit.
+	if cur.unit != nil && *cur.unit != "" {
+		if strings.HasSuffix(strings.TrimSuffix(mfn, "_"+*cur.unit), "_total") {
+			v.addMetricFamilyError(mfn, cur.pos, errMustCounterUnitPrecedeTotal)
+		}
+	}
 	for _, m := range cur.metrics {
 		mn := m.lset.Get(labels.MetricName)
 		if strings.HasSuffix(mn, "_total") {
 			if m.value < 0 || math.IsNaN(m.value) {
-				v.addMetricError(m, errMustCounterValueBeValid)
+				v.addMetricErrorAt(m, m.columns.value, errMustCounterValueBeValid)
 			}
 		}
 	}
@@ -682,19 +1496,45 @@ func (v *OpenMetricsValidator) validateMetricFamilyCounter(cur *metricFamily) {
 
 func (v *OpenMetricsValidator) validateMetricFamilyInfo(mfn string, cur *metricFamily) {
 	if cur.unit != nil && *cur.unit != "" {
-		v.addMetricFamilyError(mfn, errMustNoUnitForInfo)
+		v.addMetricFamilyError(mfn, cur.pos, errMustNoUnitForInfo)
 	}
 }
 
 func (v *OpenMetricsValidator) validateMetricFamilyStateSet(mfn string, cur *metricFamily) {
 	if cur.unit != nil && *cur.unit != "" {
-		v.addMetricFamilyError(mfn, errMustNoUnitForStateSet)
+		v.addMetricFamilyError(mfn, cur.pos, errMustNoUnitForStateSet)
 	}
 	for _, m := range cur.metrics {
 		if !m.lset.Has(mfn) {
 			v.addMetricError(m, errMustStateSetContainLabel)
 		}
 	}
+	for _, point := range cur.metricPoints() {
+		v.validateStateSetPoint(mfn, point)
+	}
+}
+
+// validateStateSetPoint validates the samples of a single StateSet MetricPoint.
+func (v *OpenMetricsValidator) validateStateSetPoint(mfn string, point []metric) {
+	var (
+		seen       = make(map[string]bool, len(point))
+		trueStates int
+	)
+	for _, m := range point {
+		state := m.lset.Get(mfn)
+		if seen[state] {
+			v.addMetricError(m, errMustStateSetHaveOneSamplePerState.withMessage(
+				"state %q appears more than once in the MetricPoint", state))
+		}
+		seen[state] = true
+		if m.value == 1 {
+			trueStates++
+		}
+	}
+	if v.enumStateSets[mfn] && trueStates != 1 {
+		v.addMetricError(point[0], errMustEnumStateSetHaveOneTrueState.withMessage(
+			"%v: found %d true states", errMustEnumStateSetHaveOneTrueState.err, trueStates))
+	}
 }
 
 func (v *OpenMetricsValidator) validateMetricFamilySummary(mfn string, cur *metricFamily) {
@@ -703,7 +1543,7 @@ func (v *OpenMetricsValidator) validateMetricFamilySummary(mfn string, cur *metr
 		if strings.HasSuffix(mn, "_count") ||
 			strings.HasSuffix(mn, "_sum") {
 			if m.value < 0 || math.IsNaN(m.value) {
-				v.addMetricError(m, errInvalidSummaryCountAndSum)
+				v.addMetricErrorAt(m, m.columns.value, errInvalidSummaryCountAndSum)
 			}
 			continue
 		}
@@ -712,12 +1552,12 @@ func (v *OpenMetricsValidator) validateMetricFamilySummary(mfn string, cur *metr
 		}
 		// Metrics with empty suffix are expected be quantiles.
 		if m.value < 0 {
-			v.addMetricError(m, errMustNotSummaryQuantileValueBeNegative)
+			v.addMetricErrorAt(m, m.columns.value, errMustNotSummaryQuantileValueBeNegative)
 		}
 		strVal := m.lset.Get("quantile")
 		val, err := strconv.ParseFloat(strVal, 64)
 		if err != nil {
-			v.addMetricError(m, fmt.Errorf("invalid quantile value %q: %v", strVal, err))
+			v.addMetricError(m, errMustSummaryQuantileBeValid.withMessage("invalid quantile value %q: %v", strVal, err))
 			continue
 		}
 		if val < 0 || val > 1 || math.IsNaN(val) {
@@ -727,22 +1567,29 @@ func (v *OpenMetricsValidator) validateMetricFamilySummary(mfn string, cur *metr
 }
 
 func (v *OpenMetricsValidator) validateMetricFamilyHistogram(mfn string, cur *metricFamily) {
+	for _, point := range cur.metricPoints() {
+		v.validateHistogramPoint(point)
+	}
+}
+
+// validateHistogramPoint validates the samples of a single histogram MetricPoint.
+func (v *OpenMetricsValidator) validateHistogramPoint(point []metric) {
 	var (
-		positiveInfBucketFound bool
-		negativeBucketFound    bool
-		sumFound               bool
-		countFound             bool
-		byBucket               = make([]histogramMetric, 0, len(cur.metrics))
+		positiveInfBucket   *metric
+		count               *metric
+		negativeBucketFound bool
+		sumFound            bool
+		byBucket            = make([]histogramMetric, 0, len(point))
 	)
 	// Histogram MetricPoints MUST have at least a bucket with an +Inf threshold.
-	for _, m := range cur.orderedByAppearance {
+	for i, m := range point {
 		mn := m.lset.Get(labels.MetricName)
 		if strings.HasSuffix(mn, "_sum") {
 			sumFound = true
 			continue
 		}
 		if strings.HasSuffix(mn, "_count") {
-			countFound = true
+			count = &point[i]
 			continue
 		}
 		if strings.HasSuffix(mn, "_created") {
@@ -754,12 +1601,12 @@ func (v *OpenMetricsValidator) validateMetricFamilyHistogram(mfn string, cur *me
 				le:     math.Inf(1),
 				metric: m,
 			})
-			positiveInfBucketFound = true
+			positiveInfBucket = &point[i]
 			continue
 		}
 		floatVal, err := strconv.ParseFloat(val, 64)
 		if err != nil {
-			v.addMetricError(m, err)
+			v.addMetricError(m, errMustBucketLabelBeValid.withMessage("%v", err))
 			continue
 		}
 		byBucket = append(byBucket, histogramMetric{
@@ -773,47 +1620,45 @@ func (v *OpenMetricsValidator) validateMetricFamilyHistogram(mfn string, cur *me
 
 	// Histogram must have increasing bucket counts since they are all counting
 	// less than or equal to with the bucket.
-	sorted := sort.SliceIsSorted(byBucket, func(i, j int) bool {
-		return byBucket[i].le < byBucket[j].le
-	})
-	if !sorted {
-		v.addMetricFamilyError(mfn, errMustHistogramBucketsInOrder)
-	} else {
-		for i := 1; i < len(byBucket); i++ {
-			last, cur := byBucket[i-1], byBucket[i]
-			if last.metric.value > cur.metric.value {
-				v.addMetricError(cur.metric, errorWithLevel{
-					err: fmt.Errorf("bucket value %v is out of order: last=%v, cur=%v",
-						cur.le, last.metric.value, cur.metric.value),
-					level: ErrorLevelMust,
-				})
-				break
-			}
-		}
+	if v.validateBucketsInOrder(byBucket, errMustHistogramBucketsInOrder) {
+		v.validateBucketsCumulative(byBucket, errMustHistogramBucketValuesIncrease)
+		v.validateBucketExemplars(byBucket)
 	}
 
-	if !positiveInfBucketFound {
-		v.addMetricFamilyError(mfn, errMustContainPositiveInfBucket)
+	if positiveInfBucket == nil {
+		v.addMetricError(point[0], errMustContainPositiveInfBucket)
 	}
-	if sumFound != countFound {
-		v.addMetricFamilyError(mfn, errMustHistogramHaveSumAndCount)
+	if sumFound != (count != nil) {
+		v.addMetricError(point[0], errMustHistogramHaveSumAndCount)
 	}
 	if sumFound && negativeBucketFound {
-		v.addMetricFamilyError(mfn, errMustHistogramNotHaveSumAndNegative)
+		v.addMetricError(point[0], errMustHistogramNotHaveSumAndNegative)
+	}
+	if count != nil && positiveInfBucket != nil && count.value != positiveInfBucket.value {
+		v.addMetricErrorAt(*count, count.columns.value, errMustHistogramCountEqualInfBucket.withMessage(
+			"%v: count=%v, +Inf bucket=%v", errMustHistogramCountEqualInfBucket.err, count.value, positiveInfBucket.value))
 	}
 }
 
 func (v *OpenMetricsValidator) validateMetricFamilyGaugeHistogram(mfn string, cur *metricFamily) {
+	for _, point := range cur.metricPoints() {
+		v.validateGaugeHistogramPoint(point)
+	}
+}
+
+// validateGaugeHistogramPoint validates the samples of a single gauge
+// histogram MetricPoint.
+func (v *OpenMetricsValidator) validateGaugeHistogramPoint(point []metric) {
 	var (
-		positiveInfBucketFound bool
-		gsumFound              bool
-		gcountFound            bool
-		negativeBucketFound    bool
-		negativeGSumFound      bool
-		byBucket               = make([]histogramMetric, 0, len(cur.metrics))
+		positiveInfBucket   *metric
+		gcount              *metric
+		gsumFound           bool
+		negativeBucketFound bool
+		negativeGSumFound   bool
+		byBucket            = make([]histogramMetric, 0, len(point))
 	)
 	// Histogram MetricPoints MUST have at least a bucket with an +Inf threshold
-	for _, m := range cur.orderedByAppearance {
+	for i, m := range point {
 		mn := m.lset.Get(labels.MetricName)
 		if strings.HasSuffix(mn, "_gsum") {
 			gsumFound = true
@@ -823,7 +1668,7 @@ func (v *OpenMetricsValidator) validateMetricFamilyGaugeHistogram(mfn string, cu
 			continue
 		}
 		if strings.HasSuffix(mn, "_gcount") {
-			gcountFound = true
+			gcount = &point[i]
 			continue
 		}
 		val := m.lset.Get(labels.BucketLabel)
@@ -832,12 +1677,12 @@ func (v *OpenMetricsValidator) validateMetricFamilyGaugeHistogram(mfn string, cu
 				le:     math.Inf(1),
 				metric: m,
 			})
-			positiveInfBucketFound = true
+			positiveInfBucket = &point[i]
 			continue
 		}
 		floatVal, err := strconv.ParseFloat(val, 64)
 		if err != nil {
-			v.addMetricError(m, err)
+			v.addMetricError(m, errMustBucketLabelBeValid.withMessage("%v", err))
 			continue
 		}
 		byBucket = append(byBucket, histogramMetric{
@@ -851,21 +1696,71 @@ func (v *OpenMetricsValidator) validateMetricFamilyGaugeHistogram(mfn string, cu
 
 	// Histogram must have increasing bucket counts since they are all counting
 	// less than or equal to with the bucket.
-	sorted := sort.SliceIsSorted(byBucket, func(i, j int) bool {
-		return byBucket[i].le < byBucket[j].le
-	})
-	if !sorted {
-		v.addMetricFamilyError(mfn, errMustGaugeHistogramBucketsInOrder)
+	if v.validateBucketsInOrder(byBucket, errMustGaugeHistogramBucketsInOrder) {
+		v.validateBucketsCumulative(byBucket, errMustGaugeHistogramBucketValuesIncrease)
+		v.validateBucketExemplars(byBucket)
 	}
 
-	if !positiveInfBucketFound {
-		v.addMetricFamilyError(mfn, errMustContainPositiveInfBucket)
+	if positiveInfBucket == nil {
+		v.addMetricError(point[0], errMustContainPositiveInfBucket)
 	}
 	if negativeGSumFound && !negativeBucketFound {
-		v.addMetricFamilyError(mfn, errMustGaugeHistogramNotHaveGSumAndNegative)
+		v.addMetricError(point[0], errMustGaugeHistogramNotHaveGSumAndNegative)
+	}
+	if gsumFound != (gcount != nil) {
+		v.addMetricError(point[0], errMustGaugeHistogramHaveGSumAndGCountOrNeither)
+	}
+	if gcount != nil && positiveInfBucket != nil && gcount.value != positiveInfBucket.value {
+		v.addMetricErrorAt(*gcount, gcount.columns.value, errMustGaugeHistogramGCountEqualInfBucket.withMessage(
+			"%v: gcount=%v, +Inf bucket=%v", errMustGaugeHistogramGCountEqualInfBucket.err, gcount.value, positiveInfBucket.value))
+	}
+}
+
+// validateBucketsInOrder reports err on the first bucket whose threshold is
+// lower than the one of the previous bucket, it returns whether the buckets
+// are in order.
+func (v *OpenMetricsValidator) validateBucketsInOrder(byBucket []histogramMetric, err errorWithLevel) bool {
+	for i := 1; i < len(byBucket); i++ {
+		if byBucket[i].le < byBucket[i-1].le {
+			v.addMetricError(byBucket[i].metric, err)
+			return false
+		}
+	}
+	return true
+}
+
+// validateBucketExemplars makes sure that the exemplar of each bucket is less
+// than or equal to the threshold of the bucket. Since buckets are cumulative
+// this is all the spec requires, but the exemplar is also expected to be
+// greater than the threshold of the previous bucket. The buckets must be in order.
+func (v *OpenMetricsValidator) validateBucketExemplars(byBucket []histogramMetric) {
+	lower := math.Inf(-1)
+	for _, b := range byBucket {
+		e := b.metric.exemplar
+		switch {
+		case e == nil:
+		case !(e.Value <= b.le):
+			v.addMetricErrorAt(b.metric, b.metric.columns.exemplar, errMustExemplarBeInBucketRange.withMessage(
+				"exemplar value %v is greater than the bucket threshold %v", e.Value, b.le))
+		case !(e.Value > lower):
+			v.addMetricErrorAt(b.metric, b.metric.columns.exemplar, errShouldExemplarBeAbovePreviousBucket.withMessage(
+				"exemplar value %v is not within the bucket range (%v, %v]", e.Value, lower, b.le))
+		}
+		lower = b.le
 	}
-	if (gsumFound && !gcountFound) || (!gsumFound && gcountFound) {
-		v.addMetricFamilyError(mfn, errMustGaugeHistogramHaveGSumAndGCountOrNeither)
+}
+
+// validateBucketsCumulative reports err on the first bucket whose value is
+// lower than the one of the previous bucket, the buckets must be in order.
+func (v *OpenMetricsValidator) validateBucketsCumulative(byBucket []histogramMetric, err errorWithLevel) {
+	for i := 1; i < len(byBucket); i++ {
+		last, cur := byBucket[i-1], byBucket[i]
+		if last.metric.value > cur.metric.value {
+			v.addMetricErrorAt(cur.metric, cur.metric.columns.value, err.withMessage(
+				"bucket value %v is out of order: last=%v, cur=%v",
+				cur.le, last.metric.value, cur.metric.value))
+			return
+		}
 	}
 }
 
@@ -879,18 +1774,22 @@ func (v *OpenMetricsValidator) validateMetric(mn string, mt textparse.MetricType
 		if strings.HasSuffix(mn, "_count") || strings.HasSuffix(mn, "_sum") || strings.HasSuffix(mn, "_bucket") {
 			v.validateMetricCounterValue(mn, cur)
 		}
+		if strings.HasSuffix(mn, "_count") || strings.HasSuffix(mn, "_bucket") {
+			v.validateMetricIntegerValue(cur, errMustHistogramValueBeInteger)
+		}
 	case textparse.MetricTypeGaugeHistogram:
 		switch {
 		case strings.HasSuffix(mn, "_bucket"):
 			if math.IsNaN(cur.value) {
-				v.addMetricError(cur, errGaugeHistogramBucketValueNaN)
+				v.addMetricErrorAt(cur, cur.columns.value, errGaugeHistogramBucketValueNaN)
 			}
 			if cur.value < 0 {
-				v.addMetricError(cur, errGaugeHistogramBucketValueNegative)
+				v.addMetricErrorAt(cur, cur.columns.value, errGaugeHistogramBucketValueNegative)
 			}
+			v.validateMetricIntegerValue(cur, errMustGaugeHistogramBucketValueBeInteger)
 		case strings.HasSuffix(mn, "_gsum"):
 			if math.IsNaN(cur.value) {
-				v.addMetricError(cur, errGaugeHistogramGSumValueNaN)
+				v.addMetricErrorAt(cur, cur.columns.value, errGaugeHistogramGSumValueNaN)
 			}
 		}
 	case textparse.MetricTypeSummary:
@@ -913,7 +1812,7 @@ func (v *OpenMetricsValidator) validateExemplar(mt textparse.MetricType, cur met
 
 	// Check valid for this type at all.
 	if mt != textparse.MetricTypeGaugeHistogram && mt != textparse.MetricTypeHistogram && mt != textparse.MetricTypeCounter {
-		v.addMetricError(cur, errExemplar)
+		v.addMetricErrorAt(cur, cur.columns.exemplar, errExemplar)
 		return
 	}
 
@@ -924,64 +1823,206 @@ func (v *OpenMetricsValidator) validateExemplar(mt textparse.MetricType, cur met
 		total += utf8.RuneCountInString(l.Value)
 	}
 	if total > exemplar.ExemplarMaxLabelSetLength {
-		v.addMetricError(cur, fmt.Errorf("exemplar label contents of %d exceeds maximum of %d UTF-8 characters",
+		v.addMetricErrorAt(cur, cur.columns.exemplar, errExemplarLabelsTooLong.withMessage(
+			"exemplar label contents of %d exceeds maximum of %d UTF-8 characters",
 			total, exemplar.ExemplarMaxLabelSetLength))
 	}
+
+	// Exemplar timestamps may be slightly in the future due to clock skew
+	// between devices, so only timestamps beyond the tolerance are reported.
+	if cur.exemplar.HasTs && cur.exemplar.Ts > v.scrapeTime+v.exemplarFutureTolerance().Milliseconds() {
+		v.addMetricErrorAt(cur, cur.columns.exemplar, errShouldNotExemplarTimestampBeInFuture)
+	}
+
+	// A counter starts from 0, so the value of a single event can not be
+	// greater than the total.
+	if mt == textparse.MetricTypeCounter && cur.exemplar.Value > cur.value {
+		v.addMetricErrorAt(cur, cur.columns.exemplar, errShouldNotExemplarExceedCounterIncrease)
+	}
+}
+
+// validateCreated makes sure that the created timestamp, which is exposed in
+// seconds, is not after the timestamp of the sample.
+func (v *OpenMetricsValidator) validateCreated(cur metric) {
+	if cur.value*1000 > float64(cur.timestamp) {
+		v.addMetricErrorAt(cur, cur.columns.value, errShouldNotCreatedBeAfterTimestamp)
+	}
 }
 
 func (v *OpenMetricsValidator) validateMetricCounterValue(mn string, cur metric) {
 	if math.IsNaN(cur.value) {
-		v.addMetricError(cur, errCounterValueNaN)
+		v.addMetricErrorAt(cur, cur.columns.value, errCounterValueNaN)
 		return
 	}
 
 	if cur.value < 0 {
-		v.addMetricError(cur, errCounterValueNegative)
+		v.addMetricErrorAt(cur, cur.columns.value, errCounterValueNegative)
+	}
+}
+
+// validateMetricIntegerValue reports err when the value is not an integer,
+// NaN values are reported by the NaN rules of the metric type instead.
+func (v *OpenMetricsValidator) validateMetricIntegerValue(cur metric, err error) {
+	if !math.IsNaN(cur.value) && cur.value != math.Trunc(cur.value) {
+		v.addMetricErrorAt(cur, cur.columns.value, err)
 	}
 }
 
 func (v *OpenMetricsValidator) validateMetricInfo(mn string, cur metric) {
 	if cur.value != 1 {
-		v.addMetricError(cur, errInvalidInfoValue)
+		v.addMetricErrorAt(cur, cur.columns.value, errInvalidInfoValue)
 	}
 }
 
 func (v *OpenMetricsValidator) validateMetricStateSet(mn string, cur metric) {
 	if cur.value != 1 && cur.value != 0 {
-		v.addMetricError(cur, errInvalidStateSetValue)
+		v.addMetricErrorAt(cur, cur.columns.value, errInvalidStateSetValue)
 	}
 }
 
-// compareMetric compares the current record against last record for a metric.
-// TODO: compare more metric types.
-func (v *OpenMetricsValidator) compareMetric(mn string, mt textparse.MetricType, last, cur metric) {
+// compareMetric compares the current record against last record for a metric,
+// reset is set when the created timestamp of the MetricPoint has advanced.
+// It returns whether a counter value of the MetricPoint decreased.
+func (v *OpenMetricsValidator) compareMetric(mn string, mt textparse.MetricType, last, cur metric, reset bool) bool {
 	if cur.timestamp < last.timestamp {
-		v.addMetricError(cur, errMustTimestampIncrease)
+		v.addMetricErrorAt(cur, cur.columns.timestamp, errMustTimestampIncrease)
 	}
+	name := cur.lset.Get(labels.MetricName)
+	if isCreatedSample(name, cur.mfn, mt) {
+		// Created timestamps are compared by compareCreated.
+		return false
+	}
+	v.compareExemplar(mt, last, cur, reset)
 	switch mt {
 	case textparse.MetricTypeCounter:
-		v.compareMetricCounter(mn, last, cur)
+		return v.compareMetricCounter(last, cur, reset, errMustNotCounterValueDecrease)
+	case textparse.MetricTypeHistogram:
+		// Buckets, count and sum are all counters.
+		return v.compareMetricCounter(last, cur, reset, errMustNotHistogramValueDecrease)
+	case textparse.MetricTypeSummary:
+		// Quantiles are gauges, only count and sum are counters.
+		if name == cur.mfn+"_count" || name == cur.mfn+"_sum" {
+			return v.compareMetricCounter(last, cur, reset, errMustNotSummaryValueDecrease)
+		}
 	}
+	return false
 }
 
-func (v *OpenMetricsValidator) compareMetricCounter(mn string, last, cur metric) {
-	if cur.value < last.value {
-		v.addMetricError(cur, errMustNotCounterValueDecrease)
+// compareExemplar compares the exemplar of the current record against the
+// exemplar of the last record.
+func (v *OpenMetricsValidator) compareExemplar(mt textparse.MetricType, last, cur metric, reset bool) {
+	if cur.exemplar == nil {
+		return
+	}
+	if last.exemplar == nil {
+		if mt == textparse.MetricTypeCounter && !reset {
+			v.compareExemplarCounterIncrease(last, cur)
+		}
+		return
+	}
+	if last.exemplar.HasTs && cur.exemplar.HasTs && cur.exemplar.Ts < last.exemplar.Ts {
+		v.addMetricErrorAt(cur, cur.columns.exemplar, errShouldNotExemplarTimestampDecrease)
+	}
+	// An unchanged exemplar may annotate an event which happened before the
+	// last record, so only new exemplars are compared against the increase.
+	if mt == textparse.MetricTypeCounter && !reset && !cur.exemplar.Equals(*last.exemplar) {
+		v.compareExemplarCounterIncrease(last, cur)
 	}
 }
 
-func (v *OpenMetricsValidator) addMetricError(m metric, err error) {
-	if err == nil {
+// compareExemplarCounterIncrease makes sure that the value of a new counter
+// exemplar is not greater than the increase of the counter since the last record.
+// An exemplar greater than the total is already reported by validateExemplar.
+func (v *OpenMetricsValidator) compareExemplarCounterIncrease(last, cur metric) {
+	if cur.exemplar.Value > cur.value {
 		return
 	}
-	v.mErr = multierr.Append(v.mErr, fmt.Errorf("error for metric %s: %v", m.String(), err))
+	if increase := cur.value - last.value; increase >= 0 && cur.exemplar.Value > increase {
+		v.addMetricErrorAt(cur, cur.columns.exemplar, errShouldNotExemplarExceedCounterIncrease.withMessage(
+			"%v: exemplar=%v, increase=%v", errShouldNotExemplarExceedCounterIncrease.err, cur.exemplar.Value, increase))
+	}
 }
 
-func (v *OpenMetricsValidator) addMetricFamilyError(name string, err error) {
+// compareMetricCounter reports err when the counter value decreased and the
+// MetricPoint was not reset.
+func (v *OpenMetricsValidator) compareMetricCounter(last, cur metric, reset bool, err errorWithLevel) bool {
+	if compareValues(cur, last) >= 0 {
+		return false
+	}
+	if !reset {
+		lastValue, curValue := displayValues(last, cur)
+		v.addMetricErrorAt(cur, cur.columns.value, err.withMessage("%v: last=%v, cur=%v", err.err, lastValue, curValue))
+	}
+	return true
+}
+
+func (v *OpenMetricsValidator) addMetricError(m metric, err error) {
+	v.addMetricErrorAt(m, 0, err)
+}
+
+// addMetricErrorAt is addMetricError for the field of the sample at column,
+// e.g. its value.
+func (v *OpenMetricsValidator) addMetricErrorAt(m metric, column int, err error) {
+	pos := m.pos
+	if pos.IsValid() {
+		pos = pos.at(column)
+	}
+	v.addViolation(Violation{
+		Pos:          pos,
+		MetricFamily: m.mfn,
+		Labels:       m.lset,
+		Value:        m.value,
+		Timestamp:    m.timestamp,
+		Exemplar:     m.exemplar,
+	}, err)
+}
+
+func (v *OpenMetricsValidator) addMetricFamilyError(name string, pos Position, err error) {
+	v.addViolation(Violation{Pos: pos, MetricFamily: name}, err)
+}
+
+// addViolation records the violation for the error if its rule is enabled and
+// its level is equal or above the validator level, otherwise the error is omitted.
+func (v *OpenMetricsValidator) addViolation(vi Violation, err error) {
 	if err == nil {
 		return
 	}
-	v.mErr = multierr.Append(v.mErr, fmt.Errorf("error for metric family %s: %v", name, err))
+	if v.format == FormatPrometheusText {
+		err = openMetricsWarning(err)
+	}
+	rule, def := ruleOf(err)
+	level, enabled := v.rules.level(rule, def)
+	if !enabled || level < v.level {
+		return
+	}
+	if v.maxErrors > 0 && len(v.report.Violations) >= v.maxErrors {
+		v.report.Truncated = true
+		v.report.OmittedViolations++
+		return
+	}
+	vi.Rule, vi.Level = rule, level
+	vi.Err = err
+	v.report.Violations = append(v.report.Violations, vi)
+}
+
+// allowSeries returns whether the sample of the series is validated, new
+// series are ignored once the maximum number of series is reached.
+func (v *OpenMetricsValidator) allowSeries(mfn string, lset labels.Labels) bool {
+	if v.maxSeries <= 0 {
+		return true
+	}
+	if mf, ok := v.curMetricSet[mfn]; ok {
+		if _, ok := mf.metrics[labelKey(lset)]; ok {
+			return true
+		}
+	}
+	if v.scrapeSeries >= v.maxSeries {
+		v.report.Truncated = true
+		v.report.IgnoredSamples++
+		return false
+	}
+	v.scrapeSeries++
+	return true
 }
 
 // labelKey generates a key for the labels.
@@ -989,29 +2030,121 @@ func labelKey(lset labels.Labels) string {
 	return lset.String()
 }
 
-func (v *OpenMetricsValidator) sanitizedMetricName(mn string) string {
-	for _, vs := range _reservedSuffixes {
-		for _, suffix := range vs.suffixes {
-			if strings.HasSuffix(mn, suffix) {
-				return strings.TrimSuffix(mn, suffix)
+// resolveMetricFamily returns the name of the metric family the sample
+// belongs to, and whether the suffix of the sample name is valid for the type
+// of the metric family.
+//
+// The sample is first matched against the metric family of the most recent
+// metadata or sample, so that e.g. a gauge called "foo_count" is not mistaken
+// for the count of a summary called "foo". Otherwise it is matched against the
+// other metric families of the metric set to detect interleaving, and finally
+// the sample is assumed to start a new metric family of its own name.
+func (v *OpenMetricsValidator) resolveMetricFamily(mn string) (string, bool) {
+	if mfn := v.lastMetricFamilyName; v.curMetricSet[mfn] != nil {
+		mt := v.curMetricSet[mfn].MetricType()
+		if mn == mfn {
+			return mfn, isValidSuffix(mt, "")
+		}
+		// Samples with reserved suffixes following a typed metric family are
+		// attributed to it, even when the suffix is not valid for the type.
+		if suffix, ok := reservedSuffix(mn); ok && mt != textparse.MetricTypeUnknown &&
+			strings.TrimSuffix(mn, suffix) == mfn {
+			return mfn, isValidSuffix(mt, suffix)
+		}
+	}
+	if mf, ok := v.curMetricSet[mn]; ok {
+		return mn, isValidSuffix(mf.MetricType(), "")
+	}
+	if suffix, ok := reservedSuffix(mn); ok {
+		mfn := strings.TrimSuffix(mn, suffix)
+		if mf, ok := v.curMetricSet[mfn]; ok && isValidSuffix(mf.MetricType(), suffix) {
+			return mfn, true
+		}
+	}
+	return mn, true
+}
+
+// validateNameClashes makes sure that no sample name could be exposed by more
+// than one metric family, e.g. a gauge "foo_created" and a counter "foo".
+func (v *OpenMetricsValidator) validateNameClashes() {
+	mfns := make([]string, 0, len(v.curMetricSet))
+	for mfn := range v.curMetricSet {
+		mfns = append(mfns, mfn)
+	}
+	sort.Strings(mfns)
+	for _, mfn := range mfns {
+		mf := v.curMetricSet[mfn]
+		for _, name := range sampleNames(mfn, mf.MetricType()) {
+			for _, other := range v.metricFamiliesOfSampleName(name) {
+				otherMF := v.curMetricSet[other]
+				// Report the clash once, on the metric family which appears last.
+				if other == mfn || otherMF.pos.Offset > mf.pos.Offset ||
+					(otherMF.pos.Offset == mf.pos.Offset && other > mfn) {
+					continue
+				}
+				v.addMetricFamilyError(mfn, mf.pos, errMustNotMetricFamilyNamesClash.withMessage(
+					"sample name %q of metric family %q clashes with metric family %q", name, mfn, other))
 			}
 		}
 	}
-	return mn
 }
 
-func (v *OpenMetricsValidator) isValidMetricName(mn, mfn string, mt *textparse.MetricType) bool {
-	vs := _reservedSuffixes[*mt]
-	for _, suffix := range vs.suffixes {
-		if !strings.HasSuffix(mn, suffix) {
+// metricFamiliesOfSampleName returns the metric families in the current
+// metric set which can expose a sample with the name.
+func (v *OpenMetricsValidator) metricFamiliesOfSampleName(name string) []string {
+	var res []string
+	if mf, ok := v.curMetricSet[name]; ok && isValidSuffix(mf.MetricType(), "") {
+		res = append(res, name)
+	}
+	for _, suffix := range _allSuffixes {
+		if !strings.HasSuffix(name, suffix) {
 			continue
 		}
-		sanitizedMetricName := strings.TrimSuffix(mn, suffix)
-		return sanitizedMetricName == mfn
+		mfn := strings.TrimSuffix(name, suffix)
+		if mf, ok := v.curMetricSet[mfn]; ok && isValidSuffix(mf.MetricType(), suffix) {
+			res = append(res, mfn)
+		}
 	}
+	return res
+}
+
+// sampleNames returns the names of the samples a metric family can expose.
+func sampleNames(mfn string, mt textparse.MetricType) []string {
+	vs := _reservedSuffixes[mt]
+	names := make([]string, 0, len(vs.suffixes)+1)
 	if vs.allowEmpty {
-		return mn == mfn
+		names = append(names, mfn)
+	}
+	for _, suffix := range vs.suffixes {
+		names = append(names, mfn+suffix)
+	}
+	return names
+}
+
+// isValidSuffix returns whether sample names of the metric type can have the
+// suffix, an empty suffix is the metric family name itself.
+func isValidSuffix(mt textparse.MetricType, suffix string) bool {
+	vs, ok := _reservedSuffixes[mt]
+	if !ok {
+		return false
+	}
+	if suffix == "" {
+		return vs.allowEmpty
+	}
+	for _, s := range vs.suffixes {
+		if s == suffix {
+			return true
+		}
 	}
-	// The metric name does not match any valid suffix for the metric type.
 	return false
 }
+
+// reservedSuffix returns the longest reserved suffix of the sample name.
+func reservedSuffix(mn string) (string, bool) {
+	for _, suffix := range _allSuffixes {
+		if strings.HasSuffix(mn, suffix) && len(mn) > len(suffix) {
+			return suffix, true
+		}
+	}
+	return "", false
+}
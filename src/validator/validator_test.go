@@ -1,10 +1,14 @@
 package validator
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/multierr"
 )
@@ -21,6 +25,271 @@ a_total{a="1",foo="bar"} 2 1
 	require.Contains(t, err.Error(), errMustNotCounterValueDecrease.Error())
 }
 
+func TestValidateReport(t *testing.T) {
+	str := `# TYPE a counter
+# HELP a help
+a_total{a="1",foo="bar"} 3 2
+a_total{a="1",foo="bar"} 2 1
+# EOF`
+	v := NewValidator(ErrorLevelMust)
+	err := v.Validate([]byte(str))
+	require.Error(t, err)
+
+	report := v.Report()
+	require.Len(t, report.Violations, 2)
+	require.Equal(t, report.Err().Error(), err.Error())
+
+	vi := report.Violations[0]
+	require.Equal(t, RuleID("timestamp.monotonic"), vi.Rule)
+	require.Equal(t, ErrorLevelMust, vi.Level)
+	require.Equal(t, "a", vi.MetricFamily)
+	require.Equal(t, `{__name__="a_total", a="1", foo="bar"}`, vi.Labels.String())
+	require.Equal(t, float64(2), vi.Value)
+	require.Equal(t, int64(1000), vi.Timestamp)
+	require.True(t, errors.Is(vi, errMustTimestampIncrease))
+
+	vi = report.Violations[1]
+	require.Equal(t, RuleID("counter.monotonic"), vi.Rule)
+	require.True(t, errors.Is(vi, errMustNotCounterValueDecrease))
+	var target Violation
+	require.True(t, errors.As(err, &target))
+
+	v.Reset()
+	require.Empty(t, v.Report().Violations)
+}
+
+func TestValidateReportParseError(t *testing.T) {
+	v := NewValidator(ErrorLevelMust)
+	err := v.Validate([]byte("a 1\n"))
+	require.EqualError(t, err, "data does not end with # EOF")
+
+	report := v.Report()
+	require.Len(t, report.Violations, 1)
+	require.Equal(t, RuleID("parse"), report.Violations[0].Rule)
+	require.Empty(t, report.Violations[0].MetricFamily)
+	require.Equal(t, Position{Offset: 4, Line: 2, Column: 1}, report.Violations[0].Pos)
+}
+
+func TestValidateReportPositions(t *testing.T) {
+	str := `# TYPE a counter
+# TYPE a counter
+a_total 1
+b 1
+a_total{b="1"} 1
+# TYPE c histogram
+c_bucket{le="1"} 0
+# EOF`
+	v := testValidator(ErrorLevelMust)
+	require.Error(t, v.Validate([]byte(str)))
+
+	positions := make(map[RuleID]Position)
+	for _, vi := range v.Report().Violations {
+		positions[vi.Rule] = vi.Pos
+	}
+	require.Equal(t, map[RuleID]Position{
+		"metadata.type-repeated": {Offset: 17, Line: 2, Column: 1},
+		"family.interleaved":     {Offset: 48, Line: 5, Column: 1},
+		"histogram.inf-bucket":   {Offset: 84, Line: 7, Column: 1},
+	}, positions)
+}
+
+func TestValidateReportColumns(t *testing.T) {
+	str := `# TYPE a counter
+a_total -1
+a_total{b="c d"} 2 # {trace_id="f g"} 3
+# EOF`
+	v := NewValidator(ErrorLevelShould, WithClock(func() time.Time { return time.Unix(10, 0) }))
+	require.Error(t, v.Validate([]byte(str)))
+
+	positions := make(map[RuleID]Position)
+	for _, vi := range v.Report().Violations {
+		// Violations of the whole metric set have no position.
+		if vi.Pos.IsValid() {
+			positions[vi.Rule] = vi.Pos
+		}
+	}
+	require.Equal(t, map[RuleID]Position{
+		"counter.total-valid":       {Offset: 25, Line: 2, Column: 9},
+		"counter.value-negative":    {Offset: 25, Line: 2, Column: 9},
+		"exemplar.counter-increase": {Offset: 47, Line: 3, Column: 20},
+	}, positions)
+
+	v = testValidator(ErrorLevelMust)
+	require.NoError(t, v.Validate([]byte("# TYPE a gauge\na 1 5\n# EOF\n")))
+	require.Error(t, v.Validate([]byte("# TYPE a gauge\na 1 4\n# EOF\n")))
+	require.Equal(t, Position{Offset: 19, Line: 2, Column: 5}, v.Report().Violations[0].Pos)
+}
+
+func TestValidateAfterParseError(t *testing.T) {
+	v := testValidator(ErrorLevelMust)
+	require.NoError(t, v.Validate([]byte("# TYPE a counter\na_total 2\n# EOF\n")))
+	err := v.Validate([]byte("# TYPE a counter\na_total 3\n# TYPE b gauge\nb x\n# EOF\n"))
+	require.Error(t, err)
+	require.Len(t, v.Report().Violations, 1)
+
+	// The metric families read before the parse error are discarded, and the
+	// history of the last complete exposition is kept.
+	err = v.Validate([]byte("# TYPE a counter\na_total 1\n# TYPE b gauge\nb 1\n# EOF\n"))
+	require.True(t, errors.Is(err, errMustNotCounterValueDecrease))
+	require.Len(t, v.Report().Violations, 1)
+	require.NoError(t, v.Validate([]byte("# TYPE a counter\na_total 1\n# TYPE b gauge\nb 1\n# EOF\n")))
+	require.Empty(t, v.Report().Violations)
+}
+
+func TestValidateReader(t *testing.T) {
+	exports := []string{`# TYPE a counter
+# HELP a help
+a_total{a="1"} 3
+# TYPE b gauge
+b{a="1"} 1
+b{a="2"} 1
+a_total{a="2"} 1
+# TYPE c histogram
+c_bucket{le="1"} 0
+# EOF
+`, `# TYPE a counter
+# HELP a help
+a_total{a="1"} 2
+# TYPE c histogram
+c_bucket{le="+Inf"} 0
+# EOF`}
+
+	expected := testValidator(ErrorLevelShould)
+	actual := testValidator(ErrorLevelShould)
+	for _, export := range exports {
+		expectedErr := expected.Validate([]byte(export))
+		actualErr := actual.ValidateReader(context.Background(), strings.NewReader(export))
+		require.Equal(t, expectedErr == nil, actualErr == nil)
+	}
+	require.NotEmpty(t, actual.Report().Violations)
+	// Violations found at the end of a scrape are not reported in a stable order.
+	require.ElementsMatch(t, expected.Report().Violations, actual.Report().Violations)
+}
+
+func TestValidateReaderLargeMetricFamily(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("# TYPE a counter\n")
+	for sb.Len() < _maxChunkSize+1024 {
+		fmt.Fprintf(&sb, "a_total{a=\"%d\"} 1\n", sb.Len())
+	}
+	sb.WriteString("a_total{a=\"last\"} -1\n# EOF\n")
+	export := sb.String()
+
+	expected := testValidator(ErrorLevelShould)
+	actual := testValidator(ErrorLevelShould)
+	require.Error(t, expected.Validate([]byte(export)))
+	require.Error(t, actual.ValidateReader(context.Background(), strings.NewReader(export)))
+	require.Equal(t, expected.Report(), actual.Report())
+}
+
+func TestValidateReaderParseError(t *testing.T) {
+	tcs := []struct {
+		name        string
+		export      string
+		expectedErr string
+		expectedPos Position
+	}{
+		{
+			name: "invalid_entry_in_later_metric_family",
+			export: `# TYPE a counter
+a_total 1
+# TYPE b gauge
+b 1 1 1
+# EOF`,
+			expectedErr: `expected next entry after timestamp, got "TIMESTAMP"`,
+			expectedPos: Position{Offset: 48, Line: 4, Column: 7},
+		},
+		{
+			name: "invalid_entry_in_earlier_metric_family",
+			export: `# TYPE a counter
+a_total x
+# TYPE b gauge
+b 1
+# EOF`,
+			expectedErr: `strconv.ParseFloat: parsing "x": invalid syntax`,
+			expectedPos: Position{Offset: 25, Line: 2, Column: 9},
+		},
+		{
+			name: "missing_eof",
+			export: `# TYPE a counter
+a_total 1
+# TYPE b gauge
+b 1
+`,
+			expectedErr: "data does not end with # EOF",
+			expectedPos: Position{Offset: 46, Line: 5, Column: 1},
+		},
+		{
+			name: "data_after_eof",
+			export: `# TYPE a counter
+a_total 1
+# EOF
+a_total 1
+`,
+			expectedErr: "unexpected data after # EOF",
+			expectedPos: Position{Offset: 27, Line: 3, Column: 1},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := testValidator(ErrorLevelMust)
+			err := v.ValidateReader(context.Background(), strings.NewReader(tc.export))
+			require.EqualError(t, err, tc.expectedErr)
+
+			report := v.Report()
+			require.Len(t, report.Violations, 1)
+			require.Equal(t, RuleID("parse"), report.Violations[0].Rule)
+			require.Equal(t, tc.expectedPos, report.Violations[0].Pos)
+
+			// The metric families read before the parse error are discarded.
+			require.NoError(t, v.ValidateReader(context.Background(), strings.NewReader(`# TYPE a counter
+a_total 1
+# TYPE b gauge
+b 1
+# EOF`)))
+		})
+	}
+}
+
+func TestValidateReleasesMetricFamilies(t *testing.T) {
+	v := testValidator(ErrorLevelMust)
+	v.startScrape()
+	require.True(t, v.validate([]byte(`# TYPE a histogram
+a_bucket{le="1"} 0
+# TYPE b gauge
+b 1
+`), Position{Line: 1, Column: 1}, false))
+
+	// The complete metric family is validated and its samples released as soon
+	// as the next one starts.
+	a := v.curMetricSet["a"]
+	require.True(t, a.validated)
+	require.Empty(t, a.orderedByAppearance)
+	require.Len(t, a.metrics, 1)
+	require.True(t, errors.Is(v.report.Err(), errMustContainPositiveInfBucket))
+
+	b := v.curMetricSet["b"]
+	require.False(t, b.validated)
+	require.Len(t, b.orderedByAppearance, 1)
+}
+
+func TestValidateReaderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	v := testValidator(ErrorLevelShould)
+	err := v.ValidateReader(ctx, strings.NewReader(`# TYPE a counter
+a_total 1
+# EOF`))
+	require.Equal(t, context.Canceled, err)
+
+	// The canceled exposition is discarded.
+	require.NoError(t, v.ValidateReader(context.Background(), strings.NewReader(`# TYPE b counter
+b_total 1
+# EOF`)))
+}
+
 func TestValidateShouldAndMust(t *testing.T) {
 	tcs := []testCase{
 		{
@@ -422,127 +691,859 @@ a_s 1
 			},
 			expectedErr: errUnitAlreadySet,
 		},
-	}
-	for _, tc := range tcs {
-		t.Run(tc.name, func(t *testing.T) {
-			// Validates against both SHOULD rules and MUST rules.
-			v := testValidator(ErrorLevelShould)
-			run(t, v, tc)
-		})
-	}
-}
-
-func TestValidateMustOnly(t *testing.T) {
-	tcs := []testCase{
 		{
-			name: "bad_must_not_counter_decreasing",
+			name: "bad_unit_without_metric_name",
+			exports: []string{
+				`# UNIT
+# EOF`,
+			},
+			expectedErr: errors.New("is not a valid start token"),
+		},
+		{
+			name: "bad_unit_without_unit",
+			exports: []string{
+				`# UNIT a
+# EOF`,
+			},
+			expectedErr: errors.New("expected text in HELP"),
+		},
+		{
+			name: "bad_unit_not_name_suffix",
+			exports: []string{
+				`# UNIT a seconds
+# EOF`,
+			},
+			expectedErr: errors.New(`unit not a suffix of metric "a"`),
+		},
+		{
+			name: "bad_unit_suffix_without_underscore",
+			exports: []string{
+				`# UNIT fooseconds seconds
+# EOF`,
+			},
+			expectedErr: errors.New(`unit not a suffix of metric "fooseconds"`),
+		},
+		{
+			name: "bad_unit_trailing_space",
+			exports: []string{
+				`# UNIT a_seconds seconds 
+# EOF`,
+			},
+			expectedErr: errors.New(`unit not a suffix of metric "a_seconds"`),
+		},
+		{
+			name: "good_unit_empty",
+			exports: []string{
+				`# TYPE a info
+# UNIT a 
+a_info 1
+# EOF`,
+			},
+		},
+		{
+			name: "good_unit_counter",
+			exports: []string{
+				`# TYPE foo_seconds counter
+# UNIT foo_seconds seconds
+foo_seconds_total 1
+# EOF`,
+			},
+		},
+		{
+			name: "bad_unit_counter_after_total",
+			exports: []string{
+				`# TYPE foo_total_seconds counter
+# UNIT foo_total_seconds seconds
+foo_total_seconds_total 1
+# EOF`,
+			},
+			expectedErr: errMustCounterUnitPrecedeTotal,
+		},
+		{
+			name: "good_gauges_with_reserved_suffixes",
+			exports: []string{
+				`# TYPE foo_count gauge
+foo_count 1
+# TYPE foo_info gauge
+foo_info 1
+# EOF`,
+			},
+		},
+		{
+			name: "good_unknown_with_reserved_suffixes",
+			exports: []string{
+				`a 1
+a_total 1
+# EOF`,
+			},
+		},
+		{
+			name: "bad_gauge_sample_with_suffix",
+			exports: []string{
+				`# TYPE foo gauge
+foo 1
+foo_count 1
+# EOF`,
+			},
+			expectedErr: errMustSampleSuffixBeValid,
+		},
+		{
+			name: "bad_counter_sample_without_suffix",
 			exports: []string{
 				`# TYPE a counter
-# HELP a help
-a_total 2
+a 1
+# EOF`,
+			},
+			expectedErr: errMustSampleSuffixBeValid,
+		},
+		{
+			name: "bad_clashing_names_gauge_first",
+			exports: []string{
+				`# TYPE a_created gauge
+a_created 1
+# TYPE a counter
+a_total 1
+# EOF`,
+			},
+			expectedErr: errMustNotMetricFamilyNamesClash,
+		},
+		{
+			name: "bad_clashing_names_counter_first",
+			exports: []string{
+				`# TYPE a counter
+a_total 1
+# TYPE a_created gauge
+a_created 1
+# EOF`,
+			},
+			expectedErr: errMustNotMetricFamilyNamesClash,
+		},
+		{
+			name: "good_counter_reset_with_created",
+			exports: []string{
+				`# TYPE a counter
+a_total 5
+a_created 0.5
 # EOF`,
 				`# TYPE a counter
-# HELP a help
 a_total 1
+a_created 1.5
+# EOF`,
+			},
+		},
+		{
+			name: "bad_counter_decrease_with_same_created",
+			exports: []string{
+				`# TYPE a counter
+a_total{a="1"} 5
+a_created{a="1"} 0.5
+# EOF`,
+				`# TYPE a counter
+a_total{a="1"} 1
+a_created{a="1"} 0.5
 # EOF`,
 			},
 			expectedErr: errMustNotCounterValueDecrease,
 		},
 		{
-			name: "good_counter_increasing",
+			name: "bad_counter_decrease_with_created_of_other_series",
+			exports: []string{
+				`# TYPE a counter
+a_total{a="1"} 5
+a_created{a="1"} 0.5
+a_total{a="2"} 5
+a_created{a="2"} 0.5
+# EOF`,
+				`# TYPE a counter
+a_total{a="1"} 1
+a_created{a="1"} 0.5
+a_total{a="2"} 5
+a_created{a="2"} 1.5
+# EOF`,
+			},
+			expectedErr: errMustNotCounterValueDecrease,
+		},
+		{
+			name: "bad_created_decrease",
 			exports: []string{
 				`# TYPE a counter
-# HELP a help
 a_total 1
+a_created 0.5
 # EOF`,
 				`# TYPE a counter
-# HELP a help
 a_total 2
+a_created 0.25
 # EOF`,
 			},
+			expectedErr: errMustNotCreatedDecrease,
 		},
 		{
-			name: "bad_should_not_metric_disappearing",
+			name: "bad_created_changed_without_reset",
 			exports: []string{
 				`# TYPE a counter
-# HELP a help
 a_total 1
+a_created 0.5
 # EOF`,
-				`# TYPE b counter
-# HELP b help
-b_total 2
+				`# TYPE a counter
+a_total 5
+a_created 0.75
 # EOF`,
 			},
+			expectedErr: errShouldCreatedChangeOnlyOnReset,
 		},
 		{
-			name: "good_not_duplicate_labels",
+			// The counter was reset after the last exposition at 1s and grew
+			// past its last value.
+			name: "good_created_changed_after_last_exposition",
 			exports: []string{
-				`# TYPE a1 counter
-# HELP a1 help
-a1_total{bar="baz1"} 1
-# TYPE a2 counter
-# HELP a2 help
-a2_total{bar="baz2"} 1
+				`# TYPE a counter
+a_total 1
+a_created 0.5
+# EOF`,
+				`# TYPE a counter
+a_total 5
+a_created 1.5
 # EOF`,
 			},
 		},
 		{
-			name: "bad_should_not_duplicate_labels",
+			name: "bad_created_changed_without_reset_with_timestamps",
 			exports: []string{
-				`# TYPE a1 counter
-# HELP a1 help
-a1_total{bar="baz"} 1
-# TYPE a2 counter
-# HELP a2 help
-a2_total{bar="baz"} 1
+				`# TYPE a counter
+a_total 1 1
+a_created 0.5 1
+# EOF`,
+				`# TYPE a counter
+a_total 5 3
+a_created 1 3
 # EOF`,
 			},
+			expectedErr: errShouldCreatedChangeOnlyOnReset,
 		},
 		{
-			name: "bad_must_not_timestamp_decrease_in_metric_set",
+			name: "bad_created_after_timestamp",
 			exports: []string{
 				`# TYPE a counter
-# HELP a help
-a_total{a="1",foo="bar"} 1 2
-a_total{a="1",foo="bar"} 2 1
+a_total 1 1
+a_created 2 1
 # EOF`,
 			},
-			expectedErr: errMustTimestampIncrease,
+			expectedErr: errShouldNotCreatedBeAfterTimestamp,
 		},
 		{
-			name: "bad_must_not_timestamp_decrease_between_metric_sets",
+			name: "good_histogram_increase",
 			exports: []string{
-				`# TYPE a counter
-# HELP a help
-a_total{a="1",foo="bar"} 1 2
+				`# TYPE a histogram
+a_bucket{le="1"} 1
+a_bucket{le="+Inf"} 2
+a_count 2
+a_sum 3
 # EOF`,
-				`# TYPE a counter
-# HELP a help
-a_total{a="1",foo="bar"} 2 1
+				`# TYPE a histogram
+a_bucket{le="1"} 2
+a_bucket{le="+Inf"} 3
+a_count 3
+a_sum 4
 # EOF`,
 			},
-			expectedErr: errMustTimestampIncrease,
 		},
-	}
-
-	for _, tc := range tcs {
-		t.Run(tc.name, func(t *testing.T) {
-			// Validate against must rules only.
-			v := testValidator(ErrorLevelMust)
-			run(t, v, tc)
-		})
-	}
-}
-
-type testCase struct {
-	name        string
-	exports     []string
-	expectedErr error
-}
-
-func run(t *testing.T, v *OpenMetricsValidator, tc testCase) {
-	var mErr error
-	for _, export := range tc.exports {
+		{
+			name: "bad_histogram_bucket_decrease",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="1"} 2
+a_bucket{le="+Inf"} 2
+# EOF`,
+				`# TYPE a histogram
+a_bucket{le="1"} 1
+a_bucket{le="+Inf"} 2
+# EOF`,
+			},
+			expectedErr: errMustNotHistogramValueDecrease,
+		},
+		{
+			name: "bad_histogram_count_decrease",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 2
+a_count 2
+a_sum 3
+# EOF`,
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 2
+a_count 1
+a_sum 3
+# EOF`,
+			},
+			expectedErr: errMustNotHistogramValueDecrease,
+		},
+		{
+			name: "bad_histogram_sum_decrease",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 2
+a_count 2
+a_sum 3
+# EOF`,
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 2
+a_count 2
+a_sum 2
+# EOF`,
+			},
+			expectedErr: errMustNotHistogramValueDecrease,
+		},
+		{
+			name: "good_histogram_reset_with_created",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 2
+a_count 2
+a_sum 3
+a_created 0.5
+# EOF`,
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 1
+a_count 1
+a_sum 1
+a_created 1.5
+# EOF`,
+			},
+		},
+		{
+			name: "good_summary_quantile_decrease",
+			exports: []string{
+				`# TYPE a summary
+a{quantile="0.5"} 2
+a_count 2
+a_sum 3
+# EOF`,
+				`# TYPE a summary
+a{quantile="0.5"} 1
+a_count 3
+a_sum 4
+# EOF`,
+			},
+		},
+		{
+			name: "bad_summary_count_decrease",
+			exports: []string{
+				`# TYPE a summary
+a_count 2
+a_sum 3
+# EOF`,
+				`# TYPE a summary
+a_count 1
+a_sum 3
+# EOF`,
+			},
+			expectedErr: errMustNotSummaryValueDecrease,
+		},
+		{
+			name: "good_histogram_multiple_points",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{b="1",le="1"} 1
+a_bucket{b="1",le="+Inf"} 2
+a_count{b="1"} 2
+a_sum{b="1"} 3
+a_bucket{b="2",le="1"} 0
+a_bucket{b="2",le="+Inf"} 1
+a_count{b="2"} 1
+a_sum{b="2"} 2
+# EOF`,
+			},
+		},
+		{
+			name: "bad_histogram_point_missing_+Inf_bucket",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{b="1",le="1"} 1
+a_bucket{b="1",le="+Inf"} 2
+a_bucket{b="2",le="1"} 0
+# EOF`,
+			},
+			expectedErr: errMustContainPositiveInfBucket,
+		},
+		{
+			name: "bad_histogram_point_buckets_not_cumulative",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{b="1",le="1"} 1
+a_bucket{b="1",le="+Inf"} 2
+a_bucket{b="2",le="1"} 3
+a_bucket{b="2",le="+Inf"} 2
+# EOF`,
+			},
+			expectedErr: errMustHistogramBucketValuesIncrease,
+		},
+		{
+			name: "bad_histogram_count_not_equal_+Inf_bucket",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 2
+a_count 3
+a_sum 3
+# EOF`,
+			},
+			expectedErr: errMustHistogramCountEqualInfBucket,
+		},
+		{
+			name: "bad_histogram_bucket_not_integer",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="1"} 0.5
+a_bucket{le="+Inf"} 1
+# EOF`,
+			},
+			expectedErr: errMustHistogramValueBeInteger,
+		},
+		{
+			name: "bad_histogram_count_not_integer",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="+Inf"} 1.5
+a_count 1.5
+a_sum 3
+# EOF`,
+			},
+			expectedErr: errMustHistogramValueBeInteger,
+		},
+		{
+			name: "good_gauge_histogram_multiple_points",
+			exports: []string{
+				`# TYPE a gaugehistogram
+a_bucket{b="1",le="1"} 1
+a_bucket{b="1",le="+Inf"} 2
+a_gcount{b="1"} 2
+a_gsum{b="1"} 3
+a_bucket{b="2",le="1"} 0
+a_bucket{b="2",le="+Inf"} 1
+a_gcount{b="2"} 1
+a_gsum{b="2"} 2
+# EOF`,
+			},
+		},
+		{
+			name: "bad_gauge_histogram_buckets_not_cumulative",
+			exports: []string{
+				`# TYPE a gaugehistogram
+a_bucket{le="1"} 3
+a_bucket{le="+Inf"} 2
+# EOF`,
+			},
+			expectedErr: errMustGaugeHistogramBucketValuesIncrease,
+		},
+		{
+			name: "bad_gauge_histogram_gcount_not_equal_+Inf_bucket",
+			exports: []string{
+				`# TYPE a gaugehistogram
+a_bucket{le="+Inf"} 2
+a_gcount 3
+a_gsum 3
+# EOF`,
+			},
+			expectedErr: errMustGaugeHistogramGCountEqualInfBucket,
+		},
+		{
+			name: "bad_gauge_histogram_bucket_not_integer",
+			exports: []string{
+				`# TYPE a gaugehistogram
+a_bucket{le="+Inf"} 0.5
+# EOF`,
+			},
+			expectedErr: errMustGaugeHistogramBucketValueBeInteger,
+		},
+		{
+			name: "good_histogram_exemplars_in_bucket_range",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="1"} 1 # {a="b"} 0.5
+a_bucket{le="2"} 2 # {a="c"} 2
+a_bucket{le="+Inf"} 3 # {a="d"} 4
+a_count 3
+a_sum 7
+# EOF`,
+			},
+		},
+		{
+			name: "bad_histogram_exemplar_above_bucket",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="1"} 1 # {a="b"} 1.5
+a_bucket{le="+Inf"} 1
+# EOF`,
+			},
+			expectedErr: errMustExemplarBeInBucketRange,
+		},
+		{
+			name: "bad_gauge_histogram_exemplar_below_previous_bucket",
+			exports: []string{
+				`# TYPE a gaugehistogram
+a_bucket{le="1"} 1
+a_bucket{le="2"} 2 # {a="c"} 0.5
+a_bucket{le="+Inf"} 2
+# EOF`,
+			},
+			expectedErr: errShouldExemplarBeAbovePreviousBucket,
+		},
+		{
+			name: "bad_counter_exemplar_greater_than_total",
+			exports: []string{
+				`# TYPE a counter
+a_total 1 # {a="b"} 2
+# EOF`,
+			},
+			expectedErr: errShouldNotExemplarExceedCounterIncrease,
+		},
+		{
+			name: "bad_counter_exemplar_greater_than_increase",
+			exports: []string{
+				`# TYPE a counter
+a_total 5 # {a="b"} 1
+# EOF`,
+				`# TYPE a counter
+a_total 6 # {a="c"} 2
+# EOF`,
+			},
+			expectedErr: errShouldNotExemplarExceedCounterIncrease,
+		},
+		{
+			name: "good_counter_unchanged_exemplar",
+			exports: []string{
+				`# TYPE a counter
+a_total 5 # {a="b"} 2
+# EOF`,
+				`# TYPE a counter
+a_total 5 # {a="b"} 2
+# EOF`,
+			},
+		},
+		{
+			name: "bad_exemplar_timestamp_in_future",
+			exports: []string{
+				`# TYPE a counter
+a_total 1 # {a="b"} 0.5 1000
+# EOF`,
+			},
+			expectedErr: errShouldNotExemplarTimestampBeInFuture,
+		},
+		{
+			name: "bad_exemplar_timestamp_decrease",
+			exports: []string{
+				`# TYPE a counter
+a_total 1 # {a="b"} 0.5 1
+# EOF`,
+				`# TYPE a counter
+a_total 2 # {a="c"} 0.5 0.5
+# EOF`,
+			},
+			expectedErr: errShouldNotExemplarTimestampDecrease,
+		},
+		{
+			name: "good_stateset_multiple_points",
+			exports: []string{
+				`# TYPE a stateset
+a{a="x",b="1"} 1
+a{a="y",b="1"} 0
+a{a="x",b="2"} 0
+a{a="y",b="2"} 1
+# TYPE c gauge
+c 1
+# EOF`,
+				`# TYPE a stateset
+a{a="x",b="1"} 0
+a{a="y",b="1"} 1
+a{a="x",b="2"} 0
+a{a="y",b="2"} 1
+# TYPE c gauge
+c 1
+# EOF`,
+			},
+		},
+		{
+			name: "bad_stateset_duplicated_state",
+			exports: []string{
+				`# TYPE a stateset
+a{a="x"} 1
+a{a="y"} 0
+a{a="x"} 0
+# EOF`,
+			},
+			expectedErr: errMustStateSetHaveOneSamplePerState,
+		},
+		{
+			name: "good_stateset_state_per_timestamp",
+			exports: []string{
+				`# TYPE a stateset
+a{a="x"} 1 1
+a{a="y"} 0 1
+a{a="x"} 0 2
+a{a="y"} 1 2
+# TYPE c gauge
+c 1
+# EOF`,
+			},
+		},
+		{
+			name: "bad_stateset_states_changed",
+			exports: []string{
+				`# TYPE a stateset
+a{a="x"} 1
+a{a="y"} 0
+# EOF`,
+				`# TYPE a stateset
+a{a="x"} 1
+a{a="y"} 0
+a{a="z"} 0
+# EOF`,
+			},
+			expectedErr: errShouldStateSetStatesBeStable,
+		},
+		{
+			name: "good_summary_reset_with_created",
+			exports: []string{
+				`# TYPE a summary
+a_count 2
+a_sum 3
+a_created 0.5
+# EOF`,
+				`# TYPE a summary
+a_count 1
+a_sum 1
+a_created 1.5
+# EOF`,
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// Validates against both SHOULD rules and MUST rules.
+			v := testValidator(ErrorLevelShould)
+			run(t, v, tc)
+		})
+	}
+}
+
+func TestValidateNameClashDeterministic(t *testing.T) {
+	str := `# TYPE a histogram
+a_bucket{le="+Inf"} 1
+# TYPE a_count gauge
+a_count 1
+# TYPE a_sum gauge
+a_sum 1
+# TYPE a_bucket gauge
+a_bucket 1
+# EOF`
+	var expected []Violation
+	for i := 0; i < 10; i++ {
+		v := testValidator(ErrorLevelMust)
+		require.Error(t, v.Validate([]byte(str)))
+		report := v.Report()
+		if expected == nil {
+			expected = report.Violations
+			continue
+		}
+		require.Equal(t, expected, report.Violations)
+	}
+	var clashes []string
+	for _, vi := range expected {
+		if vi.Rule == "family.name-clash" {
+			clashes = append(clashes, vi.MetricFamily)
+		}
+	}
+	require.Equal(t, []string{"a_bucket", "a_count", "a_sum"}, clashes)
+}
+
+func TestValidateExemplarCounterIncreaseReportedOnce(t *testing.T) {
+	v := testValidator(ErrorLevelShould)
+	require.NoError(t, v.Validate([]byte("# TYPE a counter\na_total 5\n# EOF\n")))
+	// The exemplar exceeds both the total and the increase of the counter.
+	require.Error(t, v.Validate([]byte("# TYPE a counter\na_total 6 # {a=\"b\"} 7\n# EOF\n")))
+	violations := v.Report().Violations
+	require.Len(t, violations, 1)
+	require.Equal(t, RuleID("exemplar.counter-increase"), violations[0].Rule)
+}
+
+func TestValidateEnumStateSets(t *testing.T) {
+	tcs := []testCase{
+		{
+			name: "good_one_true_state",
+			exports: []string{
+				`# TYPE a stateset
+a{a="x",b="1"} 1
+a{a="y",b="1"} 0
+a{a="x",b="2"} 0
+a{a="y",b="2"} 1
+# TYPE c stateset
+c{c="x"} 0
+c{c="y"} 0
+# EOF`,
+			},
+		},
+		{
+			name: "bad_no_true_state",
+			exports: []string{
+				`# TYPE a stateset
+a{a="x"} 0
+a{a="y"} 0
+# EOF`,
+			},
+			expectedErr: errMustEnumStateSetHaveOneTrueState,
+		},
+		{
+			name: "bad_many_true_states",
+			exports: []string{
+				`# TYPE a stateset
+a{a="x",b="1"} 0
+a{a="y",b="1"} 1
+a{a="x",b="2"} 1
+a{a="y",b="2"} 1
+# EOF`,
+			},
+			expectedErr: errMustEnumStateSetHaveOneTrueState,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(ErrorLevelMust, WithEnumStateSets("a"))
+			v.nowFn = testNowFn()
+			run(t, v, tc)
+		})
+	}
+}
+
+func TestValidateMustOnly(t *testing.T) {
+	tcs := []testCase{
+		{
+			name: "bad_must_not_counter_decreasing",
+			exports: []string{
+				`# TYPE a counter
+# HELP a help
+a_total 2
+# EOF`,
+				`# TYPE a counter
+# HELP a help
+a_total 1
+# EOF`,
+			},
+			expectedErr: errMustNotCounterValueDecrease,
+		},
+		{
+			name: "good_counter_increasing",
+			exports: []string{
+				`# TYPE a counter
+# HELP a help
+a_total 1
+# EOF`,
+				`# TYPE a counter
+# HELP a help
+a_total 2
+# EOF`,
+			},
+		},
+		{
+			name: "bad_should_not_metric_disappearing",
+			exports: []string{
+				`# TYPE a counter
+# HELP a help
+a_total 1
+# EOF`,
+				`# TYPE b counter
+# HELP b help
+b_total 2
+# EOF`,
+			},
+		},
+		{
+			name: "good_not_duplicate_labels",
+			exports: []string{
+				`# TYPE a1 counter
+# HELP a1 help
+a1_total{bar="baz1"} 1
+# TYPE a2 counter
+# HELP a2 help
+a2_total{bar="baz2"} 1
+# EOF`,
+			},
+		},
+		{
+			name: "bad_should_not_duplicate_labels",
+			exports: []string{
+				`# TYPE a1 counter
+# HELP a1 help
+a1_total{bar="baz"} 1
+# TYPE a2 counter
+# HELP a2 help
+a2_total{bar="baz"} 1
+# EOF`,
+			},
+		},
+		{
+			name: "bad_must_not_timestamp_decrease_in_metric_set",
+			exports: []string{
+				`# TYPE a counter
+# HELP a help
+a_total{a="1",foo="bar"} 1 2
+a_total{a="1",foo="bar"} 2 1
+# EOF`,
+			},
+			expectedErr: errMustTimestampIncrease,
+		},
+		{
+			name: "bad_must_not_timestamp_decrease_between_metric_sets",
+			exports: []string{
+				`# TYPE a counter
+# HELP a help
+a_total{a="1",foo="bar"} 1 2
+# EOF`,
+				`# TYPE a counter
+# HELP a help
+a_total{a="1",foo="bar"} 2 1
+# EOF`,
+			},
+			expectedErr: errMustTimestampIncrease,
+		},
+		{
+			name: "bad_should_exemplar_be_above_previous_bucket",
+			exports: []string{
+				`# TYPE a histogram
+a_bucket{le="1"} 1
+a_bucket{le="2"} 2 # {a="c"} 0.5
+a_bucket{le="+Inf"} 2
+# EOF`,
+			},
+		},
+		{
+			name: "bad_should_not_created_be_after_timestamp",
+			exports: []string{
+				`# TYPE a counter
+a_total 1 1
+a_created 2 1
+# EOF`,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// Validate against must rules only.
+			v := testValidator(ErrorLevelMust)
+			run(t, v, tc)
+		})
+	}
+}
+
+type testCase struct {
+	name        string
+	exports     []string
+	expectedErr error
+}
+
+func run(t *testing.T, v *OpenMetricsValidator, tc testCase) {
+	var mErr error
+	for _, export := range tc.exports {
 		err := v.Validate([]byte(export))
 		mErr = multierr.Append(mErr, err)
 	}
@@ -551,6 +1552,10 @@ func run(t *testing.T, v *OpenMetricsValidator, tc testCase) {
 		return
 	}
 	require.Error(t, mErr)
+	if errors.Is(mErr, tc.expectedErr) {
+		// The error matches the rule of the expected sentinel error.
+		return
+	}
 	require.Contains(t, mErr.Error(), tc.expectedErr.Error())
 }
 
@@ -563,7 +1568,182 @@ func testNowFn() nowFn {
 }
 
 func testValidator(el ErrorLevel) *OpenMetricsValidator {
-	v := NewValidator(el)
-	v.nowFn = testNowFn()
-	return v
+	return NewValidator(el, WithClock(testNowFn()))
+}
+
+func TestWithClock(t *testing.T) {
+	str := `# TYPE a counter
+a_total 1 # {} 1 100
+# EOF`
+	clock := func(sec int64) func() time.Time {
+		return func() time.Time { return time.Unix(sec, 0) }
+	}
+	v := NewValidator(ErrorLevelShould, WithClock(clock(10)))
+	require.True(t, errors.Is(v.Validate([]byte(str)), errShouldNotExemplarTimestampBeInFuture))
+
+	v = NewValidator(ErrorLevelShould, WithClock(clock(200)))
+	require.NoError(t, v.Validate([]byte(str)))
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	str := `# TYPE a counter
+a_total{b="1"} -1
+a_total{b="2"} -1
+a_total{b="3"} -1
+# EOF`
+	v := NewValidator(ErrorLevelMust, WithMaxErrors(2))
+	for i := 0; i < 2; i++ {
+		require.Error(t, v.Validate([]byte(str)))
+		report := v.Report()
+		var ruleIDs []RuleID
+		for _, vi := range report.Violations {
+			ruleIDs = append(ruleIDs, vi.Rule)
+		}
+		require.Equal(t, []RuleID{"counter.value-negative", "counter.value-negative"}, ruleIDs)
+		require.True(t, report.Truncated)
+		require.Equal(t, 4, report.OmittedViolations)
+	}
+
+	// The truncation is not a violation of the exposition.
+	v = NewValidator(ErrorLevelShould, WithClock(testNowFn()), WithMaxErrors(1))
+	err := v.Validate([]byte(`# TYPE a counter
+a_total 1 # {} 1 100000
+a_total 1 # {} 1 200000
+# EOF`))
+	require.Error(t, err)
+	report := v.Report()
+	require.Len(t, report.Violations, 1)
+	require.Equal(t, ErrorLevelShould, report.Violations[0].Level)
+	require.True(t, report.Truncated)
+}
+
+func TestWithMaxSeries(t *testing.T) {
+	str := `# TYPE a counter
+a_total{b="1"} 1
+a_total{b="2"} 1
+a_total{b="3"} -1
+a_total{b="4"} -1
+# TYPE c gauge
+c 1
+# EOF`
+	v := NewValidator(ErrorLevelMust, WithMaxSeries(2))
+	for i := 0; i < 2; i++ {
+		// The samples of the ignored series are not validated, and the limit
+		// applies to each exposition.
+		require.NoError(t, v.Validate([]byte(str)))
+		report := v.Report()
+		require.Empty(t, report.Violations)
+		require.True(t, report.Truncated)
+		require.Equal(t, 3, report.IgnoredSamples)
+	}
+
+	v = NewValidator(ErrorLevelMust, WithMaxSeries(10))
+	require.Error(t, v.Validate([]byte(str)))
+	require.False(t, v.Report().Truncated)
+}
+
+func TestDisappearedSeries(t *testing.T) {
+	v := testValidator(ErrorLevelShould)
+	require.NoError(t, v.Validate([]byte(`# TYPE a counter
+# HELP a help
+a_total{pod="1"} 1
+a_total{pod="2"} 1
+a_total{pod="3"} 1
+# TYPE b gauge
+# HELP b help
+b 1
+# TYPE c histogram
+# HELP c help
+c_bucket{pod="1",le="1"} 0
+c_bucket{pod="1",le="+Inf"} 1
+c_count{pod="1"} 1
+c_sum{pod="1"} 1
+c_created{pod="1"} 0.5
+c_bucket{pod="2",le="+Inf"} 1
+c_count{pod="2"} 1
+c_sum{pod="2"} 1
+# EOF
+`)))
+	require.Error(t, v.Validate([]byte(`# TYPE a counter
+# HELP a help
+a_total{pod="3"} 1
+a_total{pod="4"} 1
+# TYPE c histogram
+# HELP c help
+c_bucket{pod="2",le="+Inf"} 1
+c_count{pod="2"} 1
+c_sum{pod="2"} 1
+# EOF
+`)))
+
+	byFamily := make(map[string]Violation)
+	for _, vi := range v.Report().Violations {
+		if vi.Rule == "series.disappeared" {
+			require.NotContains(t, byFamily, vi.MetricFamily, "one violation per metric family")
+			byFamily[vi.MetricFamily] = vi
+		}
+	}
+	require.Len(t, byFamily, 3)
+
+	vi := byFamily["a"]
+	require.Equal(t, []labels.Labels{
+		labels.FromStrings(labels.MetricName, "a", "pod", "1"),
+		labels.FromStrings(labels.MetricName, "a", "pod", "2"),
+	}, vi.Disappeared)
+	require.Equal(t, []labels.Labels{
+		labels.FromStrings(labels.MetricName, "a", "pod", "4"),
+	}, vi.Appeared)
+	require.EqualError(t, vi, "error for metric family a: metrics and samples SHOULD NOT appear and disappear "+
+		`from exposition to exposition: 2 series disappeared (a{pod="1"}, a{pod="2"}), `+
+		`1 series appeared (a{pod="4"})`)
+
+	// The samples of a histogram are a single series.
+	vi = byFamily["c"]
+	require.Equal(t, []labels.Labels{labels.FromStrings(labels.MetricName, "c", "pod", "1")}, vi.Disappeared)
+	require.Empty(t, vi.Appeared)
+	require.EqualError(t, vi, "error for metric family c: metrics and samples SHOULD NOT appear and disappear "+
+		`from exposition to exposition: 1 series disappeared (c{pod="1"}), 0 series appeared`)
+
+	vi = byFamily["b"]
+	require.Equal(t, []labels.Labels{labels.FromStrings(labels.MetricName, "b")}, vi.Disappeared)
+	require.Empty(t, vi.Appeared)
+	require.False(t, vi.Pos.IsValid())
+}
+
+func TestAppearedSeries(t *testing.T) {
+	v := testValidator(ErrorLevelShould)
+	require.NoError(t, v.Validate([]byte(`# TYPE a gauge
+# HELP a help
+a{pod="1"} 1
+# EOF
+`)))
+	require.Error(t, v.Validate([]byte(`# TYPE a gauge
+# HELP a help
+a{pod="1"} 1
+a{pod="2"} 1
+# TYPE b gauge
+# HELP b help
+b 1
+# EOF
+`)))
+
+	// Only the series appearing in a known metric family are reported.
+	violations := v.Report().Violations
+	require.Len(t, violations, 1)
+	vi := violations[0]
+	require.Equal(t, RuleID("series.disappeared"), vi.Rule)
+	require.Equal(t, "a", vi.MetricFamily)
+	require.Empty(t, vi.Disappeared)
+	require.Equal(t, []labels.Labels{labels.FromStrings(labels.MetricName, "a", "pod", "2")}, vi.Appeared)
+	require.EqualError(t, vi, "error for metric family a: metrics and samples SHOULD NOT appear and disappear "+
+		`from exposition to exposition: 0 series disappeared, 1 series appeared (a{pod="2"})`)
+}
+
+func TestListSeries(t *testing.T) {
+	var series []labels.Labels
+	for i := 0; i < _maxListedSeries+2; i++ {
+		series = append(series, labels.FromStrings(labels.MetricName, "a", "b", fmt.Sprint(i)))
+	}
+	require.True(t, strings.HasSuffix(listSeries(series), `a{b="9"}, and 2 more)`))
+	require.Empty(t, listSeries(nil))
 }